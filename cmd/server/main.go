@@ -2,29 +2,60 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
 
 	"go.uber.org/fx"
 
+	"github.com/timduly4/mcp-server/internal/auth"
 	"github.com/timduly4/mcp-server/internal/config"
 	"github.com/timduly4/mcp-server/internal/github"
+	"github.com/timduly4/mcp-server/internal/index"
 	"github.com/timduly4/mcp-server/internal/resource"
 	"github.com/timduly4/mcp-server/internal/server"
 )
 
 func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		runCommand(args[0], args[1:])
+		return
+	}
+
+	// Configuration is loaded before the app so flag and validation errors
+	// are reported on their own
+	cfg, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	app := fx.New(
 		// Provide configuration
-		fx.Provide(config.Load),
+		fx.Supply(cfg),
 
 		// Provide GitHub client
 		fx.Provide(newGitHubClient),
 
+		// Provide full-text search index
+		fx.Provide(newSearchIndex),
+
 		// Provide resource adapter
 		fx.Provide(resource.NewAdapter),
 
 		// Provide MCP server
-		fx.Provide(server.NewMCPServer),
+		fx.Provide(newMCPServer),
+
+		// Check the GitHub credentials before serving
+		fx.Invoke(verifyGitHubToken),
 
 		// Invoke server startup
 		fx.Invoke(runServer),
@@ -34,29 +65,210 @@ func main() {
 }
 
 // newGitHubClient creates a GitHub client from configuration
-func newGitHubClient(cfg *config.Config) *github.Client {
+func newGitHubClient(cfg *config.Config) (*github.Client, error) {
 	ctx := context.Background()
-	return github.NewClient(ctx, cfg.GitHubToken)
+
+	opts := clientOptions(cfg, cfg.CacheDir)
+
+	switch cfg.AuthMethod {
+	case config.AuthGitHubApp:
+		privateKey, err := os.ReadFile(cfg.GitHubAppPrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+		}
+		opts = append(opts, github.WithAppInstallation(cfg.GitHubAppID, cfg.GitHubAppInstallationID, privateKey))
+	case config.AuthOAuth:
+		store := auth.NewStore(cfg.OAuthCredentialsPath)
+		token, err := store.Load(cfg.GitHubHost())
+		if err != nil {
+			if errors.Is(err, auth.ErrNoCredentials) {
+				return nil, fmt.Errorf("%w: run %q to log in", err, os.Args[0]+" login")
+			}
+			return nil, err
+		}
+		oauthCfg := auth.NewOAuthConfig(cfg.OAuthClientID, cfg.OAuthClientSecret, cfg.GitHubHost())
+		opts = append(opts, github.WithTokenSource(auth.TokenSource(ctx, oauthCfg, store, cfg.GitHubHost(), token)))
+	}
+
+	return github.NewClient(ctx, cfg.GitHubToken, opts...)
+}
+
+// clientOptions returns the GitHub client options shared by every
+// authentication method, caching responses in cacheDir
+func clientOptions(cfg *config.Config, cacheDir string) []github.Option {
+	opts := []github.Option{
+		github.WithRetry(cfg.RateLimitMaxRetries, cfg.RateLimitMaxWait),
+	}
+	if cfg.CacheEnabled {
+		opts = append(opts, github.WithCache(cacheDir, cfg.CacheTTL))
+	}
+	if cfg.StarFetcher == config.StarFetcherGraphQL {
+		opts = append(opts, github.WithGraphQLStars())
+	}
+	if cfg.GitHubBaseURL != "" {
+		opts = append(opts, github.WithEnterpriseURLs(cfg.GitHubBaseURL, cfg.GitHubUploadURL))
+	}
+	return opts
+}
+
+// newMCPServer creates the MCP server, serving each client with its own
+// GitHub token in multi-tenant mode
+func newMCPServer(cfg *config.Config, adapter *resource.Adapter) *server.MCPServer {
+	if !cfg.MultiTenant {
+		return server.NewMCPServer(cfg, adapter)
+	}
+	return server.NewMCPServer(cfg, adapter, server.WithTenants(newTenant(cfg)))
+}
+
+// newTenant returns a function creating the GitHub client and in-memory
+// search index of a client's token. The token is checked with GitHub before
+// it is served. Each token gets its own response cache, removed when the
+// tenant is dropped, so tenants never see each other's cached responses.
+func newTenant(cfg *config.Config) server.TenantFunc {
+	tenantsDir := filepath.Join(cfg.CacheDir, "tenants")
+	if cfg.CacheEnabled {
+		// Caches of tenants from a previous run belong to no one
+		if err := os.RemoveAll(tenantsDir); err != nil {
+			log.Printf("Failed to remove stale tenant caches: %v", err)
+		}
+	}
+
+	return func(ctx context.Context, id, token string) (*server.Tenant, error) {
+		cacheDir := filepath.Join(tenantsDir, id)
+		release := func() {}
+		if cfg.CacheEnabled {
+			release = func() {
+				if err := os.RemoveAll(cacheDir); err != nil {
+					log.Printf("Failed to remove tenant cache: %v", err)
+				}
+			}
+		}
+
+		client, err := github.NewClient(context.Background(), token, clientOptions(cfg, cacheDir)...)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		if _, err := client.Whoami(ctx); err != nil {
+			release()
+			return nil, err
+		}
+
+		searchIndex, err := index.Open("", readmeFunc(cfg, client))
+		if err != nil {
+			release()
+			return nil, err
+		}
+		return &server.Tenant{Client: client, SearchIndex: searchIndex, Release: release}, nil
+	}
+}
+
+// runCommand runs a command given on the command line instead of the
+// server. args are the flags following the command.
+func runCommand(command string, args []string) {
+	var err error
+	switch command {
+	case "login":
+		err = login(args)
+	case "logout":
+		err = logout(args)
+	default:
+		err = fmt.Errorf("unknown command %q: expected login or logout", command)
+	}
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		log.Fatal(err)
+	}
+}
+
+// login signs in with the OAuth device flow and stores the resulting token
+func login(args []string) error {
+	cfg, err := config.Load(args)
+	if err != nil {
+		return err
+	}
+	if cfg.AuthMethod != config.AuthOAuth {
+		return fmt.Errorf("login requires OAUTH_CLIENT_ID to be set instead of GITHUB_TOKEN or GITHUB_APP_ID")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	oauthCfg := auth.NewOAuthConfig(cfg.OAuthClientID, cfg.OAuthClientSecret, cfg.GitHubHost())
+	token, err := auth.DeviceLogin(ctx, oauthCfg, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	if err := auth.NewStore(cfg.OAuthCredentialsPath).Save(cfg.GitHubHost(), token); err != nil {
+		return err
+	}
+	log.Printf("Logged in; credentials saved to %s", cfg.OAuthCredentialsPath)
+	return nil
+}
+
+// logout removes the stored OAuth token
+func logout(args []string) error {
+	cfg, err := config.Load(args)
+	if err != nil {
+		return err
+	}
+	if cfg.OAuthCredentialsPath == "" {
+		return fmt.Errorf("logout requires OAUTH_CLIENT_ID or OAUTH_CREDENTIALS_PATH to be set")
+	}
+
+	if err := auth.NewStore(cfg.OAuthCredentialsPath).Delete(cfg.GitHubHost()); err != nil {
+		return err
+	}
+	log.Printf("Removed stored credentials from %s", cfg.OAuthCredentialsPath)
+	return nil
+}
+
+// newSearchIndex opens the persisted full-text search index
+func newSearchIndex(cfg *config.Config, client *github.Client) (*index.Index, error) {
+	return index.Open(cfg.IndexPath, readmeFunc(cfg, client))
+}
+
+// readmeFunc returns how the search index reads READMEs, or nil when
+// README indexing is disabled
+func readmeFunc(cfg *config.Config, client *github.Client) index.ReadmeFunc {
+	if !cfg.IndexReadmes {
+		return nil
+	}
+	return func(ctx context.Context, repo github.StarredRepo) (string, error) {
+		return client.GetReadme(ctx, repo.Owner, repo.Name)
+	}
 }
 
 // runServer starts the MCP server
-func runServer(lifecycle fx.Lifecycle, srv *server.MCPServer) {
+func runServer(lifecycle fx.Lifecycle, shutdowner fx.Shutdowner, srv *server.MCPServer) {
+	// The fx start context expires once startup completes, so the server
+	// runs on its own context that is cancelled on stop
+	serverCtx, cancel := context.WithCancel(context.Background())
+
 	lifecycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			log.Println("GitHub Starred Repos MCP Server starting...")
 
 			// Start the server in a goroutine so it doesn't block fx startup
 			go func() {
-				if err := srv.Start(ctx); err != nil {
+				if err := srv.Start(serverCtx); err != nil {
 					log.Fatalf("Server failed: %v", err)
 				}
+
+				// The transport stopped on its own (e.g. stdin closed), so stop the app
+				_ = shutdowner.Shutdown()
 			}()
 
+			// Populate resources/list and watch for star changes in the
+			// background so startup is not delayed by fetching the star list
+			go srv.Watch(serverCtx)
+
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
 			log.Println("Server shutting down...")
-			return nil
+			cancel()
+			return srv.Shutdown(ctx)
 		},
 	})
 }
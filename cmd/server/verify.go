@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/config"
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+const (
+	// verifyTimeout bounds the startup check of the GitHub credentials
+	verifyTimeout = 30 * time.Second
+
+	// expiryWarning is how long before a token expires startup warns about it
+	expiryWarning = 7 * 24 * time.Hour
+)
+
+// verifyGitHubToken checks at startup that GitHub accepts the server's
+// credentials and that the token has the scopes the enabled features need,
+// so a bad token fails here instead of on the first resource read
+func verifyGitHubToken(cfg *config.Config, client *github.Client) error {
+	if !cfg.VerifyToken || cfg.MultiTenant {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+
+	identity, err := client.Whoami(ctx)
+	if errors.Is(err, github.ErrBadCredentials) {
+		return fmt.Errorf("GitHub rejected the token: it is invalid, expired or revoked. %s", renewHint(cfg))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify the GitHub credentials (set VERIFY_TOKEN=false to skip the check): %w", err)
+	}
+
+	if identity.InstallationID != 0 {
+		log.Printf("Authenticated to GitHub as app installation %d", identity.InstallationID)
+		return nil
+	}
+
+	scopes := "not reported (fine-grained token)"
+	if identity.Scopes != nil {
+		scopes = strings.Join(identity.Scopes, ", ")
+		if scopes == "" {
+			scopes = "none"
+		}
+	}
+	expiry := "never"
+	if !identity.TokenExpiry.IsZero() {
+		expiry = identity.TokenExpiry.UTC().Format(time.RFC3339)
+	}
+	log.Printf("Authenticated to GitHub as %s (token scopes: %s; expires: %s)", identity.Login, scopes, expiry)
+
+	// OAuth login tokens are refreshed before they expire
+	if cfg.AuthMethod != config.AuthOAuth && !identity.TokenExpiry.IsZero() && time.Until(identity.TokenExpiry) < expiryWarning {
+		log.Printf("Warning: the GitHub token expires on %s. %s", expiry, renewHint(cfg))
+	}
+
+	// Fine-grained tokens report no scopes; their permissions surface as
+	// errors on the calls that need them
+	if identity.Scopes == nil {
+		return nil
+	}
+	if cfg.EnableWriteTools && !identity.HasScope("public_repo") {
+		return fmt.Errorf("ENABLE_WRITE_TOOLS needs a token with the public_repo or repo scope, but the token has %s: "+
+			"add the scope or set ENABLE_WRITE_TOOLS=false", scopes)
+	}
+	if !identity.HasScope("repo") {
+		log.Printf("The GitHub token lacks the repo scope, so stars of private repositories are not listed")
+	}
+	return nil
+}
+
+// renewHint tells the user how to replace a rejected or expiring token
+func renewHint(cfg *config.Config) string {
+	if cfg.AuthMethod == config.AuthOAuth {
+		return fmt.Sprintf("Run %q to log in again.", os.Args[0]+" login")
+	}
+
+	host := cfg.GitHubHost()
+	if host == "" {
+		host = "github.com"
+	}
+	return fmt.Sprintf("Create a new token at https://%s/settings/tokens.", host)
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// Scopes are the OAuth scopes requested at login: public_repo to star and
+// unstar repositories, read:user to read the account's profile
+var Scopes = []string{"public_repo", "read:user"}
+
+// NewOAuthConfig returns the OAuth configuration of the app clientID on a
+// GitHub host, where "" is github.com. clientSecret is optional for the device
+// flow but GitHub requires it to refresh expiring tokens of GitHub Apps.
+func NewOAuthConfig(clientID, clientSecret, host string) *oauth2.Config {
+	webURL := "https://" + hostKey(host)
+
+	return &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:       webURL + "/login/oauth/authorize",
+			TokenURL:      webURL + "/login/oauth/access_token",
+			DeviceAuthURL: webURL + "/login/device/code",
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+	}
+}
+
+// DeviceLogin runs the OAuth device flow: it asks GitHub for a user code,
+// tells the user on out where to enter it, and waits until the login is
+// approved, denied or expires
+func DeviceLogin(ctx context.Context, cfg *oauth2.Config, out io.Writer) (*oauth2.Token, error) {
+	device, err := cfg.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device login: %w", err)
+	}
+
+	fmt.Fprintf(out, "Open %s and enter the code %s\n", device.VerificationURI, device.UserCode)
+	fmt.Fprintln(out, "Waiting for the login to be approved...")
+
+	token, err := cfg.DeviceAccessToken(ctx, device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete device login: %w", err)
+	}
+	return token, nil
+}
+
+// TokenSource returns a token source that starts from token, refreshes it
+// through cfg once it expires, and saves every refreshed token to store so
+// the next start picks it up. Tokens without an expiry are used as they are.
+func TokenSource(ctx context.Context, cfg *oauth2.Config, store *Store, host string, token *oauth2.Token) oauth2.TokenSource {
+	return &persistingTokenSource{
+		src:   cfg.TokenSource(ctx, token),
+		store: store,
+		host:  host,
+		last:  token.AccessToken,
+	}
+}
+
+// persistingTokenSource saves the tokens of src whenever they change
+type persistingTokenSource struct {
+	src   oauth2.TokenSource
+	store *Store
+	host  string
+
+	mu   sync.Mutex
+	last string
+}
+
+// Token implements oauth2.TokenSource
+func (s *persistingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.src.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh OAuth token: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token.AccessToken != s.last {
+		// A failed save only costs a refresh on the next start
+		if err := s.store.Save(s.host, token); err != nil {
+			log.Printf("Failed to save refreshed OAuth token: %v", err)
+		}
+		s.last = token.AccessToken
+	}
+	return token, nil
+}
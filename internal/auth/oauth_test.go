@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// newTestOAuthConfig returns an OAuth configuration whose endpoints are served by mux
+func newTestOAuthConfig(t *testing.T, mux *http.ServeMux) *oauth2.Config {
+	t.Helper()
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	cfg := NewOAuthConfig("client-id", "", "")
+	cfg.Endpoint.TokenURL = srv.URL + "/login/oauth/access_token"
+	cfg.Endpoint.DeviceAuthURL = srv.URL + "/login/device/code"
+	return cfg
+}
+
+func TestNewOAuthConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{
+			name:     "github.com",
+			host:     "",
+			expected: "https://github.com/login/device/code",
+		},
+		{
+			name:     "enterprise server",
+			host:     "ghe.example.com",
+			expected: "https://ghe.example.com/login/device/code",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewOAuthConfig("client-id", "", tt.host)
+			if cfg.Endpoint.DeviceAuthURL != tt.expected {
+				t.Errorf("DeviceAuthURL = %q, want %q", cfg.Endpoint.DeviceAuthURL, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeviceLogin(t *testing.T) {
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/device/code", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != "client-id" {
+			t.Errorf("client_id = %q, want client-id", r.FormValue("client_id"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"device_code":"dc","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":1}`)
+	})
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("device_code") != "dc" {
+			t.Errorf("device_code = %q, want dc", r.FormValue("device_code"))
+		}
+		polls++
+
+		// GitHub reports a pending login with a 200 response
+		w.Header().Set("Content-Type", "application/json")
+		if polls == 1 {
+			fmt.Fprint(w, `{"error":"authorization_pending"}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"gho_token","refresh_token":"ghr_token","expires_in":28800,"token_type":"bearer"}`)
+	})
+	cfg := newTestOAuthConfig(t, mux)
+
+	var out strings.Builder
+	token, err := DeviceLogin(context.Background(), cfg, &out)
+	if err != nil {
+		t.Fatalf("DeviceLogin() error = %v", err)
+	}
+	if token.AccessToken != "gho_token" || token.RefreshToken != "ghr_token" {
+		t.Errorf("DeviceLogin() = %+v, want gho_token with refresh token", token)
+	}
+	if !strings.Contains(out.String(), "ABCD-1234") || !strings.Contains(out.String(), "https://github.com/login/device") {
+		t.Errorf("output %q does not show the user code and verification URI", out.String())
+	}
+}
+
+func TestDeviceLogin_Denied(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/device/code", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"device_code":"dc","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":1}`)
+	})
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"error":"access_denied"}`)
+	})
+	cfg := newTestOAuthConfig(t, mux)
+
+	if _, err := DeviceLogin(context.Background(), cfg, io.Discard); err == nil {
+		t.Error("DeviceLogin() error = nil, want access denied")
+	}
+}
+
+func TestTokenSource_RefreshesAndPersists(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != "refresh_token" || r.FormValue("refresh_token") != "ghr_old" {
+			t.Errorf("unexpected token request %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"gho_new","refresh_token":"ghr_new","expires_in":28800,"token_type":"bearer"}`)
+	})
+	cfg := newTestOAuthConfig(t, mux)
+	store := NewStore(filepath.Join(t.TempDir(), "credentials.json"))
+
+	// An expiry in the past forces a refresh on first use
+	expired := &oauth2.Token{AccessToken: "gho_old", RefreshToken: "ghr_old", Expiry: time.Now().Add(-time.Minute)}
+	ts := TokenSource(context.Background(), cfg, store, "", expired)
+
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token.AccessToken != "gho_new" {
+		t.Errorf("AccessToken = %q, want gho_new", token.AccessToken)
+	}
+
+	stored, err := store.Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if stored.AccessToken != "gho_new" || stored.RefreshToken != "ghr_new" {
+		t.Errorf("stored token = %+v, want the refreshed token", stored)
+	}
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrNoCredentials is returned when the store holds no token for a host
+var ErrNoCredentials = errors.New("no stored credentials")
+
+// Store persists OAuth tokens on disk, one per GitHub host. The file is
+// readable only by its owner since the tokens grant access to the account.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewStore returns a credential store backed by the file at path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load returns the token stored for host, or ErrNoCredentials
+func (s *Store) Load(host string) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := tokens[hostKey(host)]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s in %s", ErrNoCredentials, hostKey(host), s.path)
+	}
+	return token, nil
+}
+
+// Save stores token for host, replacing any previous token
+func (s *Store) Save(host string, token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[hostKey(host)] = token
+
+	return s.write(tokens)
+}
+
+// Delete removes the token stored for host
+func (s *Store) Delete(host string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[hostKey(host)]; !ok {
+		return nil
+	}
+	delete(tokens, hostKey(host))
+
+	return s.write(tokens)
+}
+
+// read loads every stored token; a missing file holds none
+func (s *Store) read() (map[string]*oauth2.Token, error) {
+	tokens := make(map[string]*oauth2.Token)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials in %s: %w", s.path, err)
+	}
+	return tokens, nil
+}
+
+// write replaces the credentials file atomically
+func (s *Store) write(tokens map[string]*oauth2.Token) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create credentials directory %s: %w", dir, err)
+	}
+
+	// CreateTemp creates the file with mode 0600
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+
+	return nil
+}
+
+// hostKey names the store entry of host, where "" is github.com
+func hostKey(host string) string {
+	if host == "" {
+		return "github.com"
+	}
+	return host
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestStore_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "credentials.json")
+	store := NewStore(path)
+
+	if _, err := store.Load(""); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("Load() on empty store error = %v, want ErrNoCredentials", err)
+	}
+
+	expiry := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.Save("", &oauth2.Token{AccessToken: "gho_a", RefreshToken: "ghr_a", Expiry: expiry}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save("ghe.example.com", &oauth2.Token{AccessToken: "gho_b"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("credentials file mode = %o, want 600", perm)
+	}
+
+	token, err := NewStore(path).Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if token.AccessToken != "gho_a" || token.RefreshToken != "ghr_a" || !token.Expiry.Equal(expiry) {
+		t.Errorf("Load() = %+v, want the saved github.com token", token)
+	}
+
+	if err := store.Delete(""); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Load(""); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Load() after Delete() error = %v, want ErrNoCredentials", err)
+	}
+	if token, err := store.Load("ghe.example.com"); err != nil || token.AccessToken != "gho_b" {
+		t.Errorf("Load() of other host = %+v, %v, want gho_b", token, err)
+	}
+}
+
+func TestStore_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := NewStore(path).Load(""); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Errorf("Load() error = %v, want parse error", err)
+	}
+}
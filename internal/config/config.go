@@ -2,44 +2,397 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+// Supported MCP transport modes
+const (
+	TransportStdio          = "stdio"
+	TransportSSE            = "sse"
+	TransportStreamableHTTP = "http"
+)
+
+// Supported ways of authenticating to GitHub
+const (
+	AuthToken     = "token"
+	AuthGitHubApp = "app"
+	AuthOAuth     = "oauth"
+)
+
+// Supported ways of reading whole star lists from GitHub
+const (
+	StarFetcherREST    = "rest"
+	StarFetcherGraphQL = "graphql"
 )
 
 // Config holds the application configuration
 type Config struct {
+	// How the server authenticates to GitHub: AuthToken, AuthGitHubApp or
+	// AuthOAuth; empty in multi-tenant mode
+	AuthMethod string
+
+	// Whether each HTTP client supplies its own GitHub token instead of the
+	// server using one identity for everyone
+	MultiTenant bool
+
 	// GitHub personal access token
 	GitHubToken string
 
+	// GitHub App installation to authenticate as instead of GitHubToken
+	GitHubAppID             int64
+	GitHubAppInstallationID int64
+	GitHubAppPrivateKeyPath string
+
+	// GitHub Enterprise Server API and upload URLs; empty targets github.com
+	GitHubBaseURL   string
+	GitHubUploadURL string
+
 	// Server configuration
+	Transport  string
 	ServerPort string
 	ServerHost string
 
-	// OAuth configuration (for future use)
-	OAuthClientID     string
-	OAuthClientSecret string
+	// Authorization of clients of the HTTP transports: the server's resource
+	// URL, which tokens must be issued for, and the issuer or static public
+	// keys they are verified against. Disabled when neither is set.
+	MCPAuthResource       string
+	MCPAuthIssuer         string
+	MCPAuthPublicKeysPath string
+
+	// Maximum duration of a single MCP request; zero disables the limit
+	RequestTimeout time.Duration
+
+	// Number of repositories per page in resource listings and resources/list
+	PageSize int
+
+	// How often the star list is polled for changes to notify subscribers;
+	// zero disables polling
+	PollInterval time.Duration
+
+	// How whole star lists are read: StarFetcherREST or StarFetcherGraphQL
+	StarFetcher string
+
+	// Response cache configuration
+	CacheEnabled bool
+	CacheDir     string
+	CacheTTL     time.Duration
+
+	// Full-text search index location and whether README text is indexed
+	IndexPath    string
+	IndexReadmes bool
+
+	// Whether tools that modify GitHub state (star_repo, unstar_repo) are exposed
+	EnableWriteTools bool
+
+	// Whether the GitHub credentials and their scopes are checked at startup
+	VerifyToken bool
+
+	// Rate limit handling: how often a rate-limited request is retried and
+	// the longest single wait before giving up
+	RateLimitMaxRetries int
+	RateLimitMaxWait    time.Duration
+
+	// OAuth app used by the device login, and the file its tokens are kept
+	// in. The file defaults to one in the user configuration directory when
+	// OAuth is in use and is otherwise empty unless set.
+	OAuthClientID        string
+	OAuthClientSecret    string
+	OAuthCredentialsPath string
 }
 
-// Load reads configuration from environment variables
-func Load() (*Config, error) {
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN environment variable is required")
+// Load reads configuration from command-line flags, environment variables
+// and an optional YAML or TOML config file, in that order of precedence.
+// args are the command-line arguments without the program name.
+func Load(args []string) (*Config, error) {
+	src, err := newSource(args)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := loadToken(src)
+	if err != nil {
+		return nil, err
+	}
+
+	appID, installationID, privateKeyPath, err := loadGitHubApp(src)
+	if err != nil {
+		return nil, err
+	}
+
+	oauthClientID := src.get("OAUTH_CLIENT_ID")
+
+	var methods []string
+	if token != "" {
+		methods = append(methods, AuthToken)
+	}
+	if appID != 0 {
+		methods = append(methods, AuthGitHubApp)
+	}
+	if oauthClientID != "" {
+		methods = append(methods, AuthOAuth)
+	}
+
+	transport := src.getOrDefault("MCP_TRANSPORT", TransportStdio)
+	switch transport {
+	case TransportStdio, TransportSSE, TransportStreamableHTTP:
+	default:
+		return nil, fmt.Errorf("invalid %s %q: must be one of %s, %s, %s", src.name("MCP_TRANSPORT"),
+			transport, TransportStdio, TransportSSE, TransportStreamableHTTP)
+	}
+
+	multiTenant, err := strconv.ParseBool(src.getOrDefault("MULTI_TENANT", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("MULTI_TENANT"), err)
+	}
+
+	baseURL := src.get("GITHUB_BASE_URL")
+	if err := validateURL(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("GITHUB_BASE_URL"), err)
+	}
+
+	uploadURL := src.getOrDefault("GITHUB_UPLOAD_URL", baseURL)
+	if err := validateURL(uploadURL); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("GITHUB_UPLOAD_URL"), err)
+	}
+	if uploadURL != "" && baseURL == "" {
+		return nil, fmt.Errorf("GITHUB_UPLOAD_URL requires GITHUB_BASE_URL")
+	}
+
+	var authMethod string
+	switch {
+	case multiTenant && transport == TransportStdio:
+		return nil, fmt.Errorf("MULTI_TENANT requires the %s or %s transport", TransportSSE, TransportStreamableHTTP)
+	case multiTenant && len(methods) > 0:
+		return nil, fmt.Errorf("MULTI_TENANT takes GitHub tokens from clients: unset the GitHub token, GITHUB_APP_ID and OAUTH_CLIENT_ID")
+	case multiTenant:
+	case len(methods) == 0:
+		// Fall back to the token the gh CLI logged in with
+		token, err = ghHostsToken(github.HostOf(baseURL))
+		if err != nil {
+			return nil, err
+		}
+		if token == "" {
+			return nil, fmt.Errorf("a GitHub token is required: set GITHUB_TOKEN, GITHUB_TOKEN_FILE or GITHUB_TOKEN_COMMAND, " +
+				"log in with the gh CLI, or set GITHUB_APP_ID or OAUTH_CLIENT_ID")
+		}
+		authMethod = AuthToken
+	case len(methods) > 1:
+		return nil, fmt.Errorf("a GitHub token, GITHUB_APP_ID and OAUTH_CLIENT_ID are mutually exclusive")
+	default:
+		authMethod = methods[0]
+	}
+
+	authResource := src.get("MCP_AUTH_RESOURCE")
+	authIssuer := src.get("MCP_AUTH_ISSUER")
+	authPublicKeysPath := src.get("MCP_AUTH_PUBLIC_KEYS_PATH")
+	if authIssuer != "" || authPublicKeysPath != "" {
+		if transport == TransportStdio {
+			return nil, fmt.Errorf("MCP_AUTH_ISSUER and MCP_AUTH_PUBLIC_KEYS_PATH require the %s or %s transport",
+				TransportSSE, TransportStreamableHTTP)
+		}
+		if authResource == "" {
+			return nil, fmt.Errorf("MCP_AUTH_RESOURCE is required when authorization is enabled")
+		}
+	}
+	if err := validateURL(authResource); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("MCP_AUTH_RESOURCE"), err)
+	}
+	if err := validateURL(authIssuer); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("MCP_AUTH_ISSUER"), err)
+	}
+
+	starFetcher := src.getOrDefault("STAR_FETCHER", StarFetcherREST)
+	switch starFetcher {
+	case StarFetcherREST, StarFetcherGraphQL:
+	default:
+		return nil, fmt.Errorf("invalid %s %q: must be %s or %s", src.name("STAR_FETCHER"),
+			starFetcher, StarFetcherREST, StarFetcherGraphQL)
+	}
+
+	requestTimeout, err := time.ParseDuration(src.getOrDefault("REQUEST_TIMEOUT", "2m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("REQUEST_TIMEOUT"), err)
+	}
+
+	pageSize, err := strconv.Atoi(src.getOrDefault("RESOURCE_PAGE_SIZE", "50"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		return nil, fmt.Errorf("invalid %s: must be an integer between 1 and 100", src.name("RESOURCE_PAGE_SIZE"))
+	}
+
+	pollInterval, err := time.ParseDuration(src.getOrDefault("POLL_INTERVAL", "5m"))
+	if err != nil || pollInterval < 0 {
+		return nil, fmt.Errorf("invalid %s: must be a non-negative duration", src.name("POLL_INTERVAL"))
+	}
+
+	cacheEnabled, err := strconv.ParseBool(src.getOrDefault("CACHE_ENABLED", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("CACHE_ENABLED"), err)
+	}
+
+	cacheTTL, err := time.ParseDuration(src.getOrDefault("CACHE_TTL", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("CACHE_TTL"), err)
+	}
+
+	indexReadmes, err := strconv.ParseBool(src.getOrDefault("INDEX_READMES", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("INDEX_READMES"), err)
+	}
+
+	enableWriteTools, err := strconv.ParseBool(src.getOrDefault("ENABLE_WRITE_TOOLS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("ENABLE_WRITE_TOOLS"), err)
+	}
+	if enableWriteTools && authMethod == AuthGitHubApp {
+		return nil, fmt.Errorf("ENABLE_WRITE_TOOLS requires a user token: a GitHub App installation has no stars to change")
+	}
+
+	verifyToken, err := strconv.ParseBool(src.getOrDefault("VERIFY_TOKEN", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("VERIFY_TOKEN"), err)
+	}
+
+	cacheDir := src.getOrDefault("CACHE_DIR", defaultCacheDir())
+
+	maxRetries, err := strconv.Atoi(src.getOrDefault("RATE_LIMIT_MAX_RETRIES", "3"))
+	if err != nil || maxRetries < 0 {
+		return nil, fmt.Errorf("invalid %s: must be a non-negative integer", src.name("RATE_LIMIT_MAX_RETRIES"))
+	}
+
+	maxWait, err := time.ParseDuration(src.getOrDefault("RATE_LIMIT_MAX_WAIT", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", src.name("RATE_LIMIT_MAX_WAIT"), err)
+	}
+
+	credentialsPath := src.get("OAUTH_CREDENTIALS_PATH")
+	if credentialsPath == "" && authMethod == AuthOAuth {
+		if credentialsPath, err = defaultCredentialsPath(); err != nil {
+			return nil, err
+		}
 	}
 
 	cfg := &Config{
-		GitHubToken:       token,
-		ServerPort:        getEnvOrDefault("SERVER_PORT", "8080"),
-		ServerHost:        getEnvOrDefault("SERVER_HOST", "localhost"),
-		OAuthClientID:     os.Getenv("OAUTH_CLIENT_ID"),
-		OAuthClientSecret: os.Getenv("OAUTH_CLIENT_SECRET"),
+		AuthMethod:              authMethod,
+		GitHubToken:             token,
+		GitHubAppID:             appID,
+		GitHubAppInstallationID: installationID,
+		GitHubAppPrivateKeyPath: privateKeyPath,
+		GitHubBaseURL:           baseURL,
+		GitHubUploadURL:         uploadURL,
+		MultiTenant:             multiTenant,
+		Transport:               transport,
+		ServerPort:              src.getOrDefault("SERVER_PORT", "8080"),
+		ServerHost:              src.getOrDefault("SERVER_HOST", "localhost"),
+		MCPAuthResource:         authResource,
+		MCPAuthIssuer:           authIssuer,
+		MCPAuthPublicKeysPath:   authPublicKeysPath,
+		RequestTimeout:          requestTimeout,
+		PageSize:                pageSize,
+		PollInterval:            pollInterval,
+		StarFetcher:             starFetcher,
+		CacheEnabled:            cacheEnabled,
+		CacheDir:                cacheDir,
+		CacheTTL:                cacheTTL,
+		IndexPath:               src.getOrDefault("INDEX_PATH", filepath.Join(cacheDir, "index.json")),
+		IndexReadmes:            indexReadmes,
+		EnableWriteTools:        enableWriteTools,
+		VerifyToken:             verifyToken,
+		RateLimitMaxRetries:     maxRetries,
+		RateLimitMaxWait:        maxWait,
+		OAuthClientID:           oauthClientID,
+		OAuthClientSecret:       src.get("OAUTH_CLIENT_SECRET"),
+		OAuthCredentialsPath:    credentialsPath,
 	}
 
 	return cfg, nil
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// loadGitHubApp reads the GitHub App settings, which are all required once
+// GITHUB_APP_ID is set. It returns a zero app ID when no app is configured.
+func loadGitHubApp(src *source) (int64, int64, string, error) {
+	rawAppID := src.get("GITHUB_APP_ID")
+	rawInstallationID := src.get("GITHUB_APP_INSTALLATION_ID")
+	privateKeyPath := src.get("GITHUB_APP_PRIVATE_KEY_PATH")
+
+	if rawAppID == "" {
+		if rawInstallationID != "" || privateKeyPath != "" {
+			return 0, 0, "", fmt.Errorf("GITHUB_APP_INSTALLATION_ID and GITHUB_APP_PRIVATE_KEY_PATH require GITHUB_APP_ID")
+		}
+		return 0, 0, "", nil
+	}
+
+	appID, err := strconv.ParseInt(rawAppID, 10, 64)
+	if err != nil || appID < 1 {
+		return 0, 0, "", fmt.Errorf("invalid %s: must be a positive integer", src.name("GITHUB_APP_ID"))
+	}
+
+	installationID, err := strconv.ParseInt(rawInstallationID, 10, 64)
+	if err != nil || installationID < 1 {
+		return 0, 0, "", fmt.Errorf("invalid %s: must be a positive integer", src.name("GITHUB_APP_INSTALLATION_ID"))
+	}
+
+	if privateKeyPath == "" {
+		return 0, 0, "", fmt.Errorf("GITHUB_APP_PRIVATE_KEY_PATH is required with GITHUB_APP_ID")
+	}
+
+	return appID, installationID, privateKeyPath, nil
+}
+
+// Address returns the host:port the HTTP-based transports listen on
+func (c *Config) Address() string {
+	return net.JoinHostPort(c.ServerHost, c.ServerPort)
+}
+
+// MCPAuthEnabled reports whether clients of the HTTP transports must present
+// a bearer token
+func (c *Config) MCPAuthEnabled() bool {
+	return c.MCPAuthIssuer != "" || c.MCPAuthPublicKeysPath != ""
+}
+
+// GitHubHost returns the host of the configured GitHub Enterprise Server, or
+// "" for github.com
+func (c *Config) GitHubHost() string {
+	return github.HostOf(c.GitHubBaseURL)
+}
+
+// validateURL checks that value, if set, is an absolute http(s) URL
+func validateURL(value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an absolute http or https URL", value)
+	}
+	return nil
+}
+
+// defaultCacheDir returns the per-user cache directory for the server
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "github-starred-mcp")
+}
+
+// defaultCredentialsPath is the credential store in the user configuration
+// directory. Unlike the cache there is no fallback: tokens are not written
+// to the shared temporary directory.
+func defaultCredentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("OAUTH_CREDENTIALS_PATH is required: %w", err)
 	}
-	return defaultValue
+	return filepath.Join(dir, "github-starred-mcp", "credentials.json"), nil
 }
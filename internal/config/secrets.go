@@ -0,0 +1,135 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tokenCommandTimeout bounds how long GITHUB_TOKEN_COMMAND may run
+const tokenCommandTimeout = 30 * time.Second
+
+// loadToken reads the GitHub token from GITHUB_TOKEN, the file named by
+// GITHUB_TOKEN_FILE or the output of GITHUB_TOKEN_COMMAND. The three are one
+// setting: the source of highest precedence that sets any of them decides,
+// so --github-token-file overrides GITHUB_TOKEN in the environment, and
+// within that source at most one may be set. It returns "" when none is.
+func loadToken(src *source) (string, error) {
+	values := src.getLayer("GITHUB_TOKEN", "GITHUB_TOKEN_FILE", "GITHUB_TOKEN_COMMAND")
+	if len(values) > 1 {
+		return "", fmt.Errorf("GITHUB_TOKEN, GITHUB_TOKEN_FILE and GITHUB_TOKEN_COMMAND are mutually exclusive")
+	}
+	token := values["GITHUB_TOKEN"]
+	tokenFile := values["GITHUB_TOKEN_FILE"]
+	tokenCommand := values["GITHUB_TOKEN_COMMAND"]
+
+	switch {
+	case tokenFile != "":
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", src.name("GITHUB_TOKEN_FILE"), err)
+		}
+		token = strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("invalid %s: %s is empty", src.name("GITHUB_TOKEN_FILE"), tokenFile)
+		}
+	case tokenCommand != "":
+		var err error
+		token, err = runTokenCommand(tokenCommand)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %w", src.name("GITHUB_TOKEN_COMMAND"), err)
+		}
+	}
+
+	return token, nil
+}
+
+// runTokenCommand runs a credential helper and returns the token it prints.
+// The command is split at spaces and run directly, without a shell, so it
+// behaves the same on every OS.
+func runTokenCommand(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", fmt.Errorf("the command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%q failed: %w: %s", command, err, message)
+		}
+		return "", fmt.Errorf("%q failed: %w", command, err)
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("%q printed no token", command)
+	}
+	return token, nil
+}
+
+// ghHostsToken returns the token the gh CLI stored for a GitHub host in its
+// hosts.yml, or "" when there is none. host is "" for github.com. Recent gh
+// versions keep tokens in the OS keyring instead, which GITHUB_TOKEN_COMMAND
+// can read with "gh auth token".
+func ghHostsToken(host string) (string, error) {
+	if host == "" {
+		host = "github.com"
+	}
+
+	dir := ghConfigDir()
+	if dir == "" {
+		return "", nil
+	}
+
+	path := filepath.Join(dir, "hosts.yml")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read gh CLI hosts file: %w", err)
+	}
+
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", fmt.Errorf("failed to parse gh CLI hosts file %s: %w", path, err)
+	}
+	return hosts[host].OAuthToken, nil
+}
+
+// ghConfigDir returns the gh CLI configuration directory, resolved the same
+// way gh does, or "" when there is no home directory
+func ghConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	if dir := os.Getenv("AppData"); runtime.GOOS == "windows" && dir != "" {
+		return filepath.Join(dir, "GitHub CLI")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh")
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("  file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte(" \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		env     map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "no token",
+		},
+		{
+			name: "token from the environment",
+			env:  map[string]string{"GITHUB_TOKEN": "env-token"},
+			want: "env-token",
+		},
+		{
+			name:  "token file trimmed",
+			flags: map[string]string{"GITHUB_TOKEN_FILE": tokenFile},
+			want:  "file-token",
+		},
+		{
+			name:  "flag replaces the environment",
+			flags: map[string]string{"GITHUB_TOKEN_FILE": tokenFile},
+			env:   map[string]string{"GITHUB_TOKEN": "env-token"},
+			want:  "file-token",
+		},
+		{
+			name:    "empty token file",
+			flags:   map[string]string{"GITHUB_TOKEN_FILE": emptyFile},
+			wantErr: "is empty",
+		},
+		{
+			name:    "missing token file",
+			flags:   map[string]string{"GITHUB_TOKEN_FILE": filepath.Join(dir, "missing")},
+			wantErr: "failed to read",
+		},
+		{
+			name:    "two sources in one layer",
+			env:     map[string]string{"GITHUB_TOKEN": "env-token", "GITHUB_TOKEN_FILE": tokenFile},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "blank token command",
+			env:     map[string]string{"GITHUB_TOKEN_COMMAND": "   "},
+			wantErr: "the command is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range []string{"GITHUB_TOKEN", "GITHUB_TOKEN_FILE", "GITHUB_TOKEN_COMMAND"} {
+				t.Setenv(env, tt.env[env])
+			}
+
+			token, err := loadToken(&source{flags: tt.flags})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadToken() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadToken() error = %v", err)
+			}
+			if token != tt.want {
+				t.Errorf("loadToken() = %q, want %q", token, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunTokenCommand(t *testing.T) {
+	for _, command := range []string{"echo", "true", "false"} {
+		if _, err := exec.LookPath(command); err != nil {
+			t.Skipf("%s is not available: %v", command, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr string
+	}{
+		{name: "token printed", command: "echo  gho_token ", want: "gho_token"},
+		{name: "whitespace only", command: " \t ", wantErr: "the command is empty"},
+		{name: "command fails", command: "false", wantErr: "failed"},
+		{name: "no output", command: "true", wantErr: "printed no token"},
+		{name: "unknown command", command: "no-such-token-helper", wantErr: "failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := runTokenCommand(tt.command)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("runTokenCommand(%q) error = %v, want one containing %q", tt.command, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runTokenCommand(%q) error = %v", tt.command, err)
+			}
+			if token != tt.want {
+				t.Errorf("runTokenCommand(%q) = %q, want %q", tt.command, token, tt.want)
+			}
+		})
+	}
+}
+
+func TestGHHostsToken(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", dir)
+
+	// Without a hosts file there is no token and no error
+	if token, err := ghHostsToken(""); err != nil || token != "" {
+		t.Errorf("ghHostsToken() without hosts.yml = %q, %v; want no token", token, err)
+	}
+
+	hosts := "github.com:\n  oauth_token: gho_public\n  user: octocat\nghe.example.com:\n  user: octocat\n"
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "", want: "gho_public"},
+		{host: "github.com", want: "gho_public"},
+		{host: "ghe.example.com"},
+		{host: "other.example.com"},
+	}
+	for _, tt := range tests {
+		token, err := ghHostsToken(tt.host)
+		if err != nil {
+			t.Fatalf("ghHostsToken(%q) error = %v", tt.host, err)
+		}
+		if token != tt.want {
+			t.Errorf("ghHostsToken(%q) = %q, want %q", tt.host, token, tt.want)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte("github.com: [unclosed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ghHostsToken(""); err == nil {
+		t.Error("ghHostsToken() expected error for a corrupt hosts.yml, got nil")
+	}
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv names the environment variable holding the config file path
+const ConfigFileEnv = "CONFIG_FILE"
+
+// setting is one configuration value, named by its environment variable.
+// Its command-line flag is the lowercased name with dashes (--cache-ttl) and
+// its config file key the lowercased name (cache_ttl), optionally nested at
+// underscores (cache: {ttl: ...}).
+type setting struct {
+	env   string
+	usage string
+
+	// secret settings have no flag, so they never show up in process listings
+	secret bool
+}
+
+// settings lists everything Load reads
+var settings = []setting{
+	{env: "GITHUB_TOKEN", usage: "GitHub personal access token", secret: true},
+	{env: "GITHUB_TOKEN_FILE", usage: "file containing the GitHub token"},
+	{env: "GITHUB_TOKEN_COMMAND", usage: "command printing the GitHub token, e.g. \"gh auth token\""},
+	{env: "GITHUB_APP_ID", usage: "GitHub App ID to authenticate as instead of a token"},
+	{env: "GITHUB_APP_INSTALLATION_ID", usage: "installation ID of the GitHub App"},
+	{env: "GITHUB_APP_PRIVATE_KEY_PATH", usage: "PEM file of the GitHub App private key"},
+	{env: "GITHUB_BASE_URL", usage: "GitHub Enterprise Server API URL"},
+	{env: "GITHUB_UPLOAD_URL", usage: "GitHub Enterprise Server upload URL (default: the base URL)"},
+	{env: "OAUTH_CLIENT_ID", usage: "OAuth app client ID for the device login"},
+	{env: "OAUTH_CLIENT_SECRET", usage: "OAuth app client secret", secret: true},
+	{env: "OAUTH_CREDENTIALS_PATH", usage: "file the OAuth login token is stored in"},
+	{env: "MCP_TRANSPORT", usage: "transport: stdio, sse or http (default stdio)"},
+	{env: "SERVER_HOST", usage: "host the HTTP transports listen on (default localhost)"},
+	{env: "SERVER_PORT", usage: "port the HTTP transports listen on (default 8080)"},
+	{env: "MULTI_TENANT", usage: "serve each HTTP client with its own GitHub token"},
+	{env: "MCP_AUTH_RESOURCE", usage: "public URL client tokens must be issued for"},
+	{env: "MCP_AUTH_ISSUER", usage: "authorization server issuing client tokens"},
+	{env: "MCP_AUTH_PUBLIC_KEYS_PATH", usage: "PEM file of keys client tokens are signed with"},
+	{env: "REQUEST_TIMEOUT", usage: "maximum duration of an MCP request, 0 for none (default 2m)"},
+	{env: "RESOURCE_PAGE_SIZE", usage: "repositories per page of resource listings, 1-100 (default 50)"},
+	{env: "POLL_INTERVAL", usage: "how often the star list is polled, 0 to disable (default 5m)"},
+	{env: "STAR_FETCHER", usage: "how whole star lists are read: rest or graphql (default rest)"},
+	{env: "CACHE_ENABLED", usage: "cache GitHub responses on disk (default true)"},
+	{env: "CACHE_DIR", usage: "response cache directory (default: the user cache directory)"},
+	{env: "CACHE_TTL", usage: "how long cached responses are used before revalidation (default 5m)"},
+	{env: "INDEX_PATH", usage: "search index file (default: index.json in the cache directory)"},
+	{env: "INDEX_READMES", usage: "also index README text"},
+	{env: "ENABLE_WRITE_TOOLS", usage: "expose the star_repo and unstar_repo tools"},
+	{env: "VERIFY_TOKEN", usage: "check the GitHub credentials and their scopes at startup (default true)"},
+	{env: "RATE_LIMIT_MAX_RETRIES", usage: "retries of a rate-limited request (default 3)"},
+	{env: "RATE_LIMIT_MAX_WAIT", usage: "longest wait for a rate limit reset (default 1m)"},
+}
+
+// source looks up settings in command-line flags, environment variables and
+// a config file, in that order of precedence. Empty values count as unset.
+type source struct {
+	flags map[string]string
+
+	file     map[string]string
+	fileKeys map[string]string
+	filePath string
+}
+
+// get returns the value of a setting, or "" when no source sets it
+func (s *source) get(env string) string {
+	if value := s.flags[env]; value != "" {
+		return value
+	}
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+	return s.file[env]
+}
+
+// getLayer returns the values of envs from the source of highest precedence
+// that sets any of them, for alternative settings of which a higher source
+// replaces a lower one as a whole
+func (s *source) getLayer(envs ...string) map[string]string {
+	layers := []func(string) string{
+		func(env string) string { return s.flags[env] },
+		os.Getenv,
+		func(env string) string { return s.file[env] },
+	}
+	for _, layer := range layers {
+		values := make(map[string]string)
+		for _, env := range envs {
+			if value := layer(env); value != "" {
+				values[env] = value
+			}
+		}
+		if len(values) > 0 {
+			return values
+		}
+	}
+	return map[string]string{}
+}
+
+// getOrDefault returns the value of a setting, or defaultValue when no source sets it
+func (s *source) getOrDefault(env, defaultValue string) string {
+	if value := s.get(env); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// name describes where the value of a setting came from, for error messages
+func (s *source) name(env string) string {
+	switch {
+	case s.flags[env] != "":
+		return "--" + flagName(env)
+	case os.Getenv(env) != "":
+		return env
+	case s.file[env] != "":
+		return fmt.Sprintf("%s in %s", s.fileKeys[env], s.filePath)
+	default:
+		return env
+	}
+}
+
+// flagName is the command-line flag of a setting
+func flagName(env string) string {
+	return strings.ReplaceAll(strings.ToLower(env), "_", "-")
+}
+
+// newSource parses the command-line arguments and reads the config file they
+// or CONFIG_FILE name
+func newSource(args []string) (*source, error) {
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	configFile := fs.String("config", "", "YAML or TOML config file (env "+ConfigFileEnv+")")
+	values := make(map[string]*string)
+	for _, s := range settings {
+		if !s.secret {
+			values[s.env] = fs.String(flagName(s.env), "", s.usage+" (env "+s.env+")")
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	src := &source{flags: make(map[string]string)}
+	for env, value := range values {
+		src.flags[env] = *value
+	}
+
+	path := *configFile
+	if path == "" {
+		path = os.Getenv(ConfigFileEnv)
+	}
+	if path == "" {
+		return src, nil
+	}
+
+	file, keys, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	src.file = file
+	src.fileKeys = keys
+	src.filePath = path
+	return src, nil
+}
+
+// readConfigFile reads a YAML (.yaml, .yml) or TOML (.toml) config file. It
+// returns the values by environment variable name, and the key each was set
+// with in the file.
+func readConfigFile(path string) (map[string]string, map[string]string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" && ext != ".toml" {
+		return nil, nil, fmt.Errorf("unsupported config file %s: expected a .yaml, .yml or .toml extension", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var tree map[string]any
+	switch ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		if err := decoder.Decode(&tree); err != nil && err != io.EOF {
+			return nil, nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	case ".toml":
+		if _, err := toml.Decode(string(data), &tree); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	known := make(map[string]bool, len(settings))
+	for _, s := range settings {
+		known[s.env] = true
+	}
+
+	values := make(map[string]string)
+	keys := make(map[string]string)
+	if err := flattenConfig(tree, "", "", known, values, keys); err != nil {
+		return nil, nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return values, keys, nil
+}
+
+// flattenConfig collects the scalar values of a config file tree, joining
+// nested keys with underscores to find the setting they belong to
+func flattenConfig(tree map[string]any, prefix, keyPrefix string, known map[string]bool, values, keys map[string]string) error {
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		env := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		key := name
+		if prefix != "" {
+			env = prefix + "_" + env
+			key = keyPrefix + "." + name
+		}
+
+		switch value := tree[name].(type) {
+		case map[string]any:
+			if err := flattenConfig(value, env, key, known, values, keys); err != nil {
+				return err
+			}
+		case []any:
+			return fmt.Errorf("%s: lists are not supported", key)
+		case nil:
+		default:
+			if !known[env] {
+				return fmt.Errorf("unknown setting %s", key)
+			}
+			if previous, ok := keys[env]; ok {
+				return fmt.Errorf("%s and %s set the same value", previous, key)
+			}
+			values[env] = fmt.Sprint(value)
+			keys[env] = key
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// appJWTLifetime is how long an app JWT is valid; GitHub accepts at most 10 minutes
+	appJWTLifetime = 9 * time.Minute
+
+	// appJWTClockSkew backdates the JWT issue time to tolerate clock drift
+	appJWTClockSkew = time.Minute
+
+	// installationTokenRefresh is how long before expiry an installation
+	// token is replaced, so requests in flight never carry an expired token
+	installationTokenRefresh = 5 * time.Minute
+
+	// installationTokenTimeout bounds a single installation token request
+	installationTokenTimeout = 30 * time.Second
+)
+
+// appAuth identifies a GitHub App installation to authenticate as
+type appAuth struct {
+	appID          int64
+	installationID int64
+	privateKey     []byte
+}
+
+// WithAppInstallation authenticates as an installation of the GitHub App
+// appID instead of with a token. privateKey is the app's PEM-encoded private
+// key; installation access tokens are minted with it and refreshed before
+// they expire.
+func WithAppInstallation(appID, installationID int64, privateKey []byte) Option {
+	return func(o *clientOptions) {
+		o.app = &appAuth{
+			appID:          appID,
+			installationID: installationID,
+			privateKey:     privateKey,
+		}
+	}
+}
+
+// appTransport signs every request with a freshly minted GitHub App JWT
+type appTransport struct {
+	base  http.RoundTripper
+	appID int64
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := signAppJWT(t.appID, t.key, t.now())
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+// installationTokenSource mints installation access tokens for a GitHub App
+type installationTokenSource struct {
+	apps           *github.Client
+	installationID int64
+}
+
+// Token implements oauth2.TokenSource
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), installationTokenTimeout)
+	defer cancel()
+
+	token, _, err := s.apps.Apps.CreateInstallationToken(ctx, s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create installation token for installation %d: %w", s.installationID, err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		Expiry:      token.GetExpiresAt().Time,
+	}, nil
+}
+
+// newInstallationTokenSource returns a token source that mints installation
+// tokens through base, the untouched transport, and reuses each token until
+// shortly before it expires
+func newInstallationTokenSource(app *appAuth, base http.RoundTripper, options clientOptions) (oauth2.TokenSource, error) {
+	key, err := parsePrivateKey(app.privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := github.NewClient(&http.Client{Transport: &appTransport{
+		base:  base,
+		appID: app.appID,
+		key:   key,
+		now:   time.Now,
+	}})
+	if apps, err = withEnterpriseURLs(apps, options); err != nil {
+		return nil, err
+	}
+
+	src := &installationTokenSource{apps: apps, installationID: app.installationID}
+	return oauth2.ReuseTokenSourceWithExpiry(nil, src, installationTokenRefresh), nil
+}
+
+// parsePrivateKey decodes a PEM-encoded RSA private key in PKCS #1 or PKCS #8 form
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode GitHub App private key: no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: not an RSA key")
+	}
+	return key, nil
+}
+
+// signAppJWT returns the RS256-signed JWT a GitHub App authenticates with
+func signAppJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWT header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWT claims: %w", err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
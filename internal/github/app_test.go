@@ -0,0 +1,185 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestKey returns an RSA key and its PKCS #1 PEM encoding
+func newTestKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// verifyAppJWT checks the signature of an app JWT and returns its claims
+func verifyAppJWT(t *testing.T, token string, key *rsa.PublicKey) map[string]int64 {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT has %d parts, want 3", len(parts))
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("failed to decode signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("JWT signature does not verify: %v", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("failed to decode claims: %v", err)
+	}
+	var claims map[string]int64
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("failed to parse claims: %v", err)
+	}
+	return claims
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, pkcs1 := newTestKey(t)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{name: "PKCS #1", data: pkcs1},
+		{name: "PKCS #8", data: pkcs8},
+		{name: "not PEM", data: []byte("not a key"), wantErr: true},
+		{name: "not a key", data: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("garbage")}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parsePrivateKey(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePrivateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !result.Equal(key) {
+				t.Error("parsePrivateKey() returned a different key")
+			}
+		})
+	}
+}
+
+func TestSignAppJWT(t *testing.T) {
+	key, _ := newTestKey(t)
+	now := time.Unix(1700000000, 0)
+
+	token, err := signAppJWT(12345, key, now)
+	if err != nil {
+		t.Fatalf("signAppJWT() error = %v", err)
+	}
+
+	claims := verifyAppJWT(t, token, &key.PublicKey)
+	if claims["iss"] != 12345 {
+		t.Errorf("iss = %d, want 12345", claims["iss"])
+	}
+	if claims["iat"] != now.Add(-appJWTClockSkew).Unix() {
+		t.Errorf("iat = %d, want %d", claims["iat"], now.Add(-appJWTClockSkew).Unix())
+	}
+	if claims["exp"] != now.Add(appJWTLifetime).Unix() {
+		t.Errorf("exp = %d, want %d", claims["exp"], now.Add(appJWTLifetime).Unix())
+	}
+}
+
+func TestNewClient_AppInstallation(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		wantMints int
+	}{
+		{
+			name:      "token reused until near expiry",
+			expiresIn: time.Hour,
+			wantMints: 1,
+		},
+		{
+			name:      "token about to expire is refreshed",
+			expiresIn: installationTokenRefresh / 2,
+			wantMints: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, pemKey := newTestKey(t)
+
+			mints := 0
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v3/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("unexpected method %s", r.Method)
+				}
+				jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+				if claims := verifyAppJWT(t, jwt, &key.PublicKey); claims["iss"] != 7 {
+					t.Errorf("iss = %d, want 7", claims["iss"])
+				}
+
+				mints++
+				expiresAt := time.Now().Add(tt.expiresIn).UTC().Format(time.RFC3339)
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":%q}`, mints, expiresAt)
+			})
+			mux.HandleFunc("/api/v3/repos/mark3labs/mcp-go", func(w http.ResponseWriter, r *http.Request) {
+				if want := fmt.Sprintf("Bearer ghs_%d", mints); r.Header.Get("Authorization") != want {
+					t.Errorf("Authorization = %q, want %q", r.Header.Get("Authorization"), want)
+				}
+				fmt.Fprint(w, `{"full_name":"mark3labs/mcp-go"}`)
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			client, err := NewClient(context.Background(), "",
+				WithEnterpriseURLs(srv.URL, ""),
+				WithAppInstallation(7, 42, pemKey),
+			)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			for range 2 {
+				if _, err := client.GetRepo(context.Background(), "mark3labs", "mcp-go"); err != nil {
+					t.Fatalf("GetRepo() error = %v", err)
+				}
+			}
+			if mints != tt.wantMints {
+				t.Errorf("installation tokens minted = %d, want %d", mints, tt.wantMints)
+			}
+		})
+	}
+}
+
+func TestNewClient_AppInstallationInvalidKey(t *testing.T) {
+	if _, err := NewClient(context.Background(), "", WithAppInstallation(7, 42, []byte("not a key"))); err == nil {
+		t.Error("NewClient() error = nil, want invalid key error")
+	}
+}
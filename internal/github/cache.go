@@ -0,0 +1,400 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// cacheMaxAge is how long an entry is kept after it was last stored or
+	// revalidated; entries unused for longer are removed from disk
+	cacheMaxAge = 7 * 24 * time.Hour
+
+	// pruneInterval is how often the cache directory is swept for such entries
+	pruneInterval = time.Hour
+)
+
+// uncachedKey is the context key marking requests that bypass the response cache
+type uncachedKey struct{}
+
+// withoutCache returns a copy of ctx whose requests bypass the response
+// cache, for reads whose headers must be live, such as the token scopes
+func withoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, uncachedKey{}, true)
+}
+
+// isUncached reports whether ctx was made by withoutCache
+func isUncached(ctx context.Context) bool {
+	uncached, _ := ctx.Value(uncachedKey{}).(bool)
+	return uncached
+}
+
+// cacheEntry is a cached GitHub API response stored on disk
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	StoredAt     time.Time   `json:"stored_at"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// cacheTransport is an http.RoundTripper that caches GET responses on disk.
+// Entries younger than ttl are served without touching the network; older
+// entries are revalidated with If-None-Match/If-Modified-Since so that an
+// unchanged page costs a 304, which GitHub does not count against the rate limit.
+// A successful write request (such as starring a repository) makes every
+// existing entry stale, so reads after a write see its effect. Entries not
+// used for cacheMaxAge, or the TTL if longer, are pruned.
+type cacheTransport struct {
+	base   http.RoundTripper
+	dir    string
+	ttl    time.Duration
+	maxAge time.Duration
+	now    func() time.Time
+
+	// identity, when set, names the account requests are made as and keys
+	// entries in place of the Authorization header
+	identity string
+
+	mu            sync.Mutex
+	invalidatedAt time.Time
+	prunedAt      time.Time
+}
+
+// newCacheTransport creates a caching transport that stores entries in dir
+func newCacheTransport(base http.RoundTripper, dir string, ttl time.Duration) (*cacheTransport, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	t := &cacheTransport{
+		base:   base,
+		dir:    dir,
+		ttl:    ttl,
+		maxAge: max(cacheMaxAge, ttl),
+		now:    time.Now,
+	}
+	t.pruneIfDue()
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.base.RoundTrip(req)
+		// GraphQL queries are POSTs too but do not change anything
+		if err == nil && resp.StatusCode < http.StatusBadRequest && !strings.HasSuffix(req.URL.Path, "/graphql") {
+			t.invalidate()
+		}
+		return resp, err
+	}
+
+	// The rate limit status must always be live, as must anything read
+	// through an uncached context
+	if req.Method != http.MethodGet || strings.HasSuffix(req.URL.Path, "/rate_limit") || isUncached(req.Context()) {
+		return t.base.RoundTrip(req)
+	}
+
+	key := t.cacheKey(req)
+	entry := t.load(key)
+
+	if entry != nil && t.fresh(entry) {
+		return entry.response(req), nil
+	}
+
+	outReq := req
+	if entry != nil {
+		// RoundTrippers must not modify the caller's request
+		outReq = req.Clone(req.Context())
+		if entry.ETag != "" {
+			outReq.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			outReq.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+
+		// Keep the fresh rate limit headers from the 304
+		for _, h := range []string{"X-Ratelimit-Limit", "X-Ratelimit-Remaining", "X-Ratelimit-Reset", "X-Ratelimit-Used"} {
+			if v := resp.Header.Get(h); v != "" {
+				entry.Header.Set(h, v)
+			}
+		}
+		entry.StoredAt = t.now()
+		t.store(key, entry)
+
+		return entry.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.store(key, &cacheEntry{
+		URL:          req.URL.String(),
+		ETag:         etag,
+		LastModified: lastModified,
+		StoredAt:     t.now(),
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+	})
+
+	return resp, nil
+}
+
+// fresh reports whether entry can be served without revalidation
+func (t *cacheTransport) fresh(entry *cacheEntry) bool {
+	return t.freshSince(entry.StoredAt)
+}
+
+// freshSince reports whether something stored at storedAt is younger than
+// the TTL and was stored after the last write
+func (t *cacheTransport) freshSince(storedAt time.Time) bool {
+	t.mu.Lock()
+	invalidatedAt := t.invalidatedAt
+	t.mu.Unlock()
+
+	return t.now().Sub(storedAt) < t.ttl && storedAt.After(invalidatedAt)
+}
+
+// invalidate marks every entry stored so far as stale. Entries are
+// revalidated rather than dropped, so unchanged pages still cost only a 304.
+func (t *cacheTransport) invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.invalidatedAt = t.now()
+}
+
+// cacheKey identifies a request by URL, media type and credentials so that
+// different accounts never share cached responses. The credentials are the
+// identity, when set, and otherwise the token.
+func (t *cacheTransport) cacheKey(req *http.Request) string {
+	credentials := req.Header.Get("Authorization")
+	if t.identity != "" {
+		credentials = "identity " + t.identity
+	}
+
+	h := sha256.New()
+	h.Write([]byte(credentials))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Header.Get("Accept")))
+	h.Write([]byte{0})
+	h.Write([]byte(req.URL.String()))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (t *cacheTransport) path(key string) string {
+	return filepath.Join(t.dir, key+".json")
+}
+
+// load reads a cache entry, treating unreadable or corrupt entries as misses
+func (t *cacheTransport) load(key string) *cacheEntry {
+	data, err := os.ReadFile(t.path(key))
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// store writes a cache entry atomically. Failures are logged and otherwise
+// ignored since the cache is only an optimisation.
+func (t *cacheTransport) store(key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Failed to encode cache entry for %s: %v", entry.URL, err)
+		return
+	}
+
+	tmp, err := os.CreateTemp(t.dir, key+".*.tmp")
+	if err != nil {
+		log.Printf("Failed to write cache entry for %s: %v", entry.URL, err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		log.Printf("Failed to write cache entry for %s: %v", entry.URL, err)
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		log.Printf("Failed to write cache entry for %s: %v", entry.URL, err)
+		return
+	}
+	if err := os.Rename(tmp.Name(), t.path(key)); err != nil {
+		log.Printf("Failed to write cache entry for %s: %v", entry.URL, err)
+	}
+
+	t.pruneIfDue()
+}
+
+// pruneIfDue prunes the cache directory unless it was pruned within the last
+// pruneInterval
+func (t *cacheTransport) pruneIfDue() {
+	t.mu.Lock()
+	now := t.now()
+	due := now.Sub(t.prunedAt) >= pruneInterval
+	if due {
+		t.prunedAt = now
+	}
+	t.mu.Unlock()
+
+	if due {
+		t.prune()
+	}
+}
+
+// prune removes the entries, and leftover temporary files, that were last
+// written more than maxAge ago. Other files in the directory, which may be
+// shared with the search index, are left alone.
+func (t *cacheTransport) prune() {
+	files, err := os.ReadDir(t.dir)
+	if err != nil {
+		log.Printf("Failed to prune the response cache: %v", err)
+		return
+	}
+
+	cutoff := t.now().Add(-t.maxAge)
+	for _, file := range files {
+		if file.IsDir() || !isCacheFile(file.Name()) {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(t.dir, file.Name())); err != nil && !os.IsNotExist(err) {
+			log.Printf("Failed to remove cache entry %s: %v", file.Name(), err)
+		}
+	}
+}
+
+// isCacheFile reports whether name is a cache entry written by store, or a
+// temporary file it left behind
+func isCacheFile(name string) bool {
+	key, _, _ := strings.Cut(name, ".")
+	if len(key) != hex.EncodedLen(sha256.Size) || !strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".tmp") {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
+
+// response builds an HTTP response from the cached entry
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set("X-From-Cache", "1")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// resultCache keeps decoded GraphQL results, which the response cache does
+// not store since GraphQL queries are POSTs, in memory for the TTL of the
+// response cache. A write through the response cache makes them stale too.
+// A nil resultCache caches nothing.
+type resultCache struct {
+	transport *cacheTransport
+
+	mu      sync.Mutex
+	entries map[string]resultEntry
+}
+
+// resultEntry is a cached result and when it was stored
+type resultEntry struct {
+	value    any
+	storedAt time.Time
+}
+
+func newResultCache(transport *cacheTransport) *resultCache {
+	return &resultCache{
+		transport: transport,
+		entries:   make(map[string]resultEntry),
+	}
+}
+
+// get returns the result stored under key, if it is still fresh
+func (r *resultCache) get(key string) (any, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[key]
+	if !ok || !r.transport.freshSince(entry.storedAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// put stores a result under key, dropping the entries that went stale
+func (r *resultCache) put(key string, value any) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, entry := range r.entries {
+		if !r.transport.freshSince(entry.storedAt) {
+			delete(r.entries, k)
+		}
+	}
+	r.entries[key] = resultEntry{value: value, storedAt: r.transport.now()}
+}
@@ -0,0 +1,294 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newETagServer returns a test server that serves body with a fixed ETag and
+// answers matching If-None-Match requests with 304
+func newETagServer(t *testing.T, body string, hits, notModified *int32) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func doGet(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestCacheTransport_FreshEntryServedFromDisk(t *testing.T) {
+	var hits, notModified int32
+	srv := newETagServer(t, `[{"id":1}]`, &hits, &notModified)
+
+	transport, err := newCacheTransport(http.DefaultTransport, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("newCacheTransport() error = %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 3; i++ {
+		status, body := doGet(t, client, srv.URL+"/user/starred")
+		if status != http.StatusOK {
+			t.Errorf("status = %d, want 200", status)
+		}
+		if body != `[{"id":1}]` {
+			t.Errorf("body = %q, want cached body", body)
+		}
+	}
+
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1", hits)
+	}
+}
+
+func TestCacheTransport_StaleEntryRevalidated(t *testing.T) {
+	var hits, notModified int32
+	srv := newETagServer(t, `[{"id":1}]`, &hits, &notModified)
+
+	transport, err := newCacheTransport(http.DefaultTransport, t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatalf("newCacheTransport() error = %v", err)
+	}
+	now := time.Now()
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	doGet(t, client, srv.URL+"/user/starred")
+
+	// Expire the entry
+	now = now.Add(2 * time.Minute)
+
+	status, body := doGet(t, client, srv.URL+"/user/starred")
+	if status != http.StatusOK {
+		t.Errorf("status = %d, want 200 for revalidated entry", status)
+	}
+	if body != `[{"id":1}]` {
+		t.Errorf("body = %q, want cached body", body)
+	}
+	if hits != 2 {
+		t.Errorf("server hits = %d, want 2", hits)
+	}
+	if notModified != 1 {
+		t.Errorf("304 responses = %d, want 1", notModified)
+	}
+}
+
+func TestCacheTransport_KeyedByCredentials(t *testing.T) {
+	var hits, notModified int32
+	srv := newETagServer(t, `[]`, &hits, &notModified)
+
+	transport, err := newCacheTransport(http.DefaultTransport, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("newCacheTransport() error = %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	for _, token := range []string{"token-a", "token-b"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/user/starred", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if hits != 2 {
+		t.Errorf("server hits = %d, want 2 (one per token)", hits)
+	}
+}
+
+// TestCacheTransport_KeyedByIdentity tests that with an identity set the
+// tokens of one account, such as rotated installation tokens, share entries
+func TestCacheTransport_KeyedByIdentity(t *testing.T) {
+	var hits, notModified int32
+	srv := newETagServer(t, `[]`, &hits, &notModified)
+
+	transport, err := newCacheTransport(http.DefaultTransport, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("newCacheTransport() error = %v", err)
+	}
+	transport.identity = "app 7 installation 42"
+	client := &http.Client{Transport: transport}
+
+	for _, token := range []string{"ghs_first", "ghs_rotated"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/user/starred", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1 (the rotated token reuses the entry)", hits)
+	}
+}
+
+func TestCacheTransport_NonGETNotCached(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	transport, err := newCacheTransport(http.DefaultTransport, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("newCacheTransport() error = %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodPut, srv.URL+"/user/starred/o/r", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("PUT error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if hits != 2 {
+		t.Errorf("server hits = %d, want 2", hits)
+	}
+}
+
+func TestCacheTransport_WriteInvalidates(t *testing.T) {
+	var hits, notModified int32
+	srv := newETagServer(t, `[{"id":1}]`, &hits, &notModified)
+
+	transport, err := newCacheTransport(http.DefaultTransport, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("newCacheTransport() error = %v", err)
+	}
+	now := time.Now()
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	doGet(t, client, srv.URL+"/user/starred")
+
+	now = now.Add(time.Second)
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/user/starred/o/r", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("PUT error = %v", err)
+	}
+	resp.Body.Close()
+
+	// The entry is still within its TTL but must be revalidated after the write
+	now = now.Add(time.Second)
+	doGet(t, client, srv.URL+"/user/starred")
+	doGet(t, client, srv.URL+"/user/starred")
+
+	if hits != 3 {
+		t.Errorf("server hits = %d, want 3 (GET, PUT, revalidating GET)", hits)
+	}
+	if notModified != 1 {
+		t.Errorf("304 responses = %d, want 1", notModified)
+	}
+}
+
+func TestCacheTransport_GraphQLDoesNotInvalidate(t *testing.T) {
+	var hits, notModified int32
+	srv := newETagServer(t, `[{"id":1}]`, &hits, &notModified)
+
+	transport, err := newCacheTransport(http.DefaultTransport, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("newCacheTransport() error = %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	doGet(t, client, srv.URL+"/user/starred")
+
+	resp, err := client.Post(srv.URL+"/graphql", "application/json", strings.NewReader(`{"query":"{ viewer { login } }"}`))
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	resp.Body.Close()
+
+	doGet(t, client, srv.URL+"/user/starred")
+
+	if hits != 2 {
+		t.Errorf("server hits = %d, want 2 (GET and GraphQL POST only)", hits)
+	}
+}
+
+func TestCacheTransport_PrunesUnusedEntries(t *testing.T) {
+	var hits, notModified int32
+	srv := newETagServer(t, `[{"id":1}]`, &hits, &notModified)
+
+	dir := t.TempDir()
+	transport, err := newCacheTransport(http.DefaultTransport, dir, time.Minute)
+	if err != nil {
+		t.Fatalf("newCacheTransport() error = %v", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	doGet(t, client, srv.URL+"/user/starred")
+	doGet(t, client, srv.URL+"/user/repos")
+
+	// One entry goes unused for longer than cacheMaxAge, as does a file of
+	// the search index sharing the directory
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/user/starred", nil)
+	unused := transport.path(transport.cacheKey(req))
+	indexPath := filepath.Join(dir, "index.json")
+	if err := os.WriteFile(indexPath, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-cacheMaxAge - time.Hour)
+	for _, path := range []string{unused, indexPath} {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	transport.prune()
+
+	if _, err := os.Stat(unused); !os.IsNotExist(err) {
+		t.Errorf("unused entry still exists (stat error %v)", err)
+	}
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Errorf("search index was removed: %v", err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("cache directory holds %d files, want the recent entry and the index", len(files))
+	}
+}
@@ -2,16 +2,39 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/oauth2"
 )
 
+// maxPerPage is the largest page size the GitHub API accepts
+const maxPerPage = 100
+
+// ErrNotFound is returned when a repository does not exist or is not visible to the token
+var ErrNotFound = errors.New("not found")
+
 // Client wraps the GitHub API client
 type Client struct {
 	client *github.Client
-	ctx    context.Context
+
+	// Retry behaviour for rate-limited requests
+	maxRetries int
+	maxWait    time.Duration
+
+	// Whether whole star lists are read through the GraphQL API
+	graphQLStars bool
+
+	// GitHub App installation the client authenticates as, if any
+	installationID int64
+
+	// results caches GraphQL results; nil when the response cache is disabled
+	results *resultCache
 }
 
 // StarredRepo represents a starred repository with relevant metadata
@@ -26,113 +49,362 @@ type StarredRepo struct {
 	Forks       int
 	UpdatedAt   string
 	Owner       string
+	Topics      []string
+
+	// License is the SPDX identifier of the license, or its name when GitHub
+	// has no SPDX identifier for it
+	License       string
+	DefaultBranch string
+	Archived      bool
+	Fork          bool
+	IsTemplate    bool
+	OpenIssues    int
+	PushedAt      string
+	Homepage      string
+	Visibility    string
+
+	// StarredAt is when the repository was starred, as an RFC 3339 UTC
+	// timestamp. It is only set for repositories read from a star list.
+	StarredAt string
 }
 
-// NewClient creates a new GitHub API client with OAuth token
-func NewClient(ctx context.Context, token string) *Client {
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+// Option configures optional Client behaviour
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	cacheDir      string
+	cacheTTL      time.Duration
+	cacheIdentity string
+	maxRetries    int
+	maxWait       time.Duration
+	graphQLStars  bool
+	baseURL       string
+	uploadURL     string
+	app           *appAuth
+	tokenSource   oauth2.TokenSource
+}
 
-	return &Client{
-		client: github.NewClient(tc),
-		ctx:    ctx,
+// WithCache enables the on-disk response cache stored in dir. Responses
+// younger than ttl are served from disk; older ones are revalidated with ETags.
+// Responses are keyed by the token they were fetched with, so a token that
+// changes, such as a refreshed OAuth token, starts over with an empty cache
+// unless WithCacheIdentity names the account instead. GitHub App
+// installations are keyed by app and installation ID without it.
+func WithCache(dir string, ttl time.Duration) Option {
+	return func(o *clientOptions) {
+		o.cacheDir = dir
+		o.cacheTTL = ttl
 	}
 }
 
-// GetStarredRepos fetches all starred repositories for the authenticated user
-func (c *Client) GetStarredRepos() ([]StarredRepo, error) {
-	var allRepos []StarredRepo
-	opts := &github.ActivityListStarredOptions{
-		ListOptions: github.ListOptions{PerPage: 100},
+// WithCacheIdentity keys cached responses by identity instead of the token,
+// so they outlive token rotation. identity must name a single GitHub account
+// on the client's host, as a login does.
+func WithCacheIdentity(identity string) Option {
+	return func(o *clientOptions) {
+		o.cacheIdentity = identity
+	}
+}
+
+// WithRetry sets how often a rate-limited request is retried and the longest
+// single wait the client accepts before giving up
+func WithRetry(maxRetries int, maxWait time.Duration) Option {
+	return func(o *clientOptions) {
+		o.maxRetries = maxRetries
+		o.maxWait = maxWait
+	}
+}
+
+// WithGraphQLStars reads whole star lists through the GraphQL API, which
+// returns every StarredRepo field for 100 stars per request. Paginated
+// listings keep using the REST API, whose page numbers they expose.
+func WithGraphQLStars() Option {
+	return func(o *clientOptions) {
+		o.graphQLStars = true
+	}
+}
+
+// WithEnterpriseURLs targets a GitHub Enterprise Server instead of github.com.
+// baseURL is the server's URL, such as https://github.example.com/ (the
+// /api/v3/ suffix is added when missing); uploadURL defaults to baseURL.
+func WithEnterpriseURLs(baseURL, uploadURL string) Option {
+	return func(o *clientOptions) {
+		o.baseURL = baseURL
+		o.uploadURL = uploadURL
+	}
+}
+
+// WithTokenSource authenticates with the tokens of ts instead of a fixed
+// token, such as OAuth tokens that are refreshed as they expire
+func WithTokenSource(ts oauth2.TokenSource) Option {
+	return func(o *clientOptions) {
+		o.tokenSource = ts
+	}
+}
+
+// NewClient creates a new GitHub API client with OAuth token, or with the
+// tokens of WithAppInstallation or WithTokenSource when one is given.
+// ctx is only used to build the HTTP client; each API call takes its own context.
+func NewClient(ctx context.Context, token string, opts ...Option) (*Client, error) {
+	options := clientOptions{
+		maxRetries: defaultMaxRetries,
+		maxWait:    defaultMaxWait,
+	}
+	for _, opt := range opts {
+		opt(&options)
 	}
 
-	for {
-		repos, resp, err := c.client.Activity.ListStarred(c.ctx, "", opts)
+	var ts oauth2.TokenSource
+	switch {
+	case options.app != nil:
+		var err error
+		ts, err = newInstallationTokenSource(options.app, http.DefaultTransport, options)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch starred repos: %w", err)
+			return nil, err
 		}
+	case options.tokenSource != nil:
+		ts = options.tokenSource
+	default:
+		ts = oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: token},
+		)
+	}
 
-		for _, repo := range repos {
-			if repo.Repository == nil {
-				continue
+	var transport http.RoundTripper = http.DefaultTransport
+	var results *resultCache
+	if options.cacheDir != "" {
+		cache, err := newCacheTransport(transport, options.cacheDir, options.cacheTTL)
+		if err != nil {
+			log.Printf("Response cache disabled: %v", err)
+		} else {
+			cache.identity = options.cacheIdentity
+			if cache.identity == "" && options.app != nil {
+				// Installation tokens are replaced hourly
+				cache.identity = fmt.Sprintf("app %d installation %d", options.app.appID, options.app.installationID)
 			}
+			transport = cache
+			results = newResultCache(cache)
+		}
+	}
 
-			r := repo.Repository
-			starredRepo := StarredRepo{
-				Name:        getStringValue(r.Name),
-				FullName:    getStringValue(r.FullName),
-				Description: getStringValue(r.Description),
-				URL:         getStringValue(r.URL),
-				HTMLURL:     getStringValue(r.HTMLURL),
-				Language:    getStringValue(r.Language),
-				Stars:       getIntValue(r.StargazersCount),
-				Forks:       getIntValue(r.ForksCount),
-				Owner:       getOwnerLogin(r.Owner),
-			}
+	// The cache sits below the OAuth transport so it sees the Authorization header
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
 
-			if r.UpdatedAt != nil {
-				starredRepo.UpdatedAt = r.UpdatedAt.String()
-			}
+	tc := oauth2.NewClient(ctx, ts)
 
-			allRepos = append(allRepos, starredRepo)
-		}
+	client, err := withEnterpriseURLs(github.NewClient(tc), options)
+	if err != nil {
+		return nil, err
+	}
 
-		if resp.NextPage == 0 {
-			break
-		}
-		opts.Page = resp.NextPage
+	c := &Client{
+		client:       client,
+		maxRetries:   options.maxRetries,
+		maxWait:      options.maxWait,
+		graphQLStars: options.graphQLStars,
+		results:      results,
+	}
+	if options.app != nil {
+		c.installationID = options.app.installationID
 	}
+	return c, nil
+}
 
-	return allRepos, nil
+// withEnterpriseURLs points client at the GitHub Enterprise Server in
+// options, if any
+func withEnterpriseURLs(client *github.Client, options clientOptions) (*github.Client, error) {
+	if options.baseURL == "" {
+		return client, nil
+	}
+
+	uploadURL := options.uploadURL
+	if uploadURL == "" {
+		uploadURL = options.baseURL
+	}
+
+	client, err := client.WithEnterpriseURLs(options.baseURL, uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure GitHub Enterprise URLs: %w", err)
+	}
+	return client, nil
+}
+
+// Host returns the host name of the GitHub Enterprise Server the client
+// talks to, or "" for github.com
+func (c *Client) Host() string {
+	return HostOf(c.client.BaseURL.String())
+}
+
+// HostOf returns the host name of the GitHub Enterprise Server an API base
+// URL belongs to, or "" for github.com, whose API is at api.github.com
+func HostOf(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "api.github.com" {
+		return ""
+	}
+	return u.Host
+}
+
+// IsInstallation reports whether the client authenticates as a GitHub App
+// installation, which is not a user and has no stars of its own
+func (c *Client) IsInstallation() bool {
+	return c.installationID != 0
+}
+
+// GetStarredRepos fetches all starred repositories for the authenticated user
+func (c *Client) GetStarredRepos(ctx context.Context) ([]StarredRepo, error) {
+	repos, err := c.listAllStarred(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch starred repos: %w", err)
+	}
+	return repos, nil
 }
 
 // GetStarredReposForUser fetches starred repositories for a specific user
-func (c *Client) GetStarredReposForUser(username string) ([]StarredRepo, error) {
-	var allRepos []StarredRepo
-	opts := &github.ActivityListStarredOptions{
-		ListOptions: github.ListOptions{PerPage: 100},
+func (c *Client) GetStarredReposForUser(ctx context.Context, username string) ([]StarredRepo, error) {
+	repos, err := c.listAllStarred(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch starred repos for user %s: %w", username, err)
 	}
+	return repos, nil
+}
 
-	for {
-		repos, resp, err := c.client.Activity.ListStarred(c.ctx, username, opts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch starred repos for user %s: %w", username, err)
+// GetStarredReposPage fetches a single page of starred repositories, most
+// recently starred first, and returns the number of the next page, or 0 on the
+// last page. An empty username lists the authenticated user's stars.
+func (c *Client) GetStarredReposPage(ctx context.Context, username string, page, perPage int) ([]StarredRepo, int, error) {
+	repos, nextPage, err := c.listStarredPage(ctx, username, page, perPage)
+	if err != nil {
+		if username == "" {
+			return nil, 0, fmt.Errorf("failed to fetch starred repos: %w", err)
 		}
+		return nil, 0, fmt.Errorf("failed to fetch starred repos for user %s: %w", username, err)
+	}
+	return repos, nextPage, nil
+}
 
-		for _, repo := range repos {
-			if repo.Repository == nil {
-				continue
-			}
+// listAllStarred follows the pagination of a star list to the end
+func (c *Client) listAllStarred(ctx context.Context, username string) ([]StarredRepo, error) {
+	if c.graphQLStars {
+		return c.listAllStarredGraphQL(ctx, username)
+	}
 
-			r := repo.Repository
-			starredRepo := StarredRepo{
-				Name:        getStringValue(r.Name),
-				FullName:    getStringValue(r.FullName),
-				Description: getStringValue(r.Description),
-				URL:         getStringValue(r.URL),
-				HTMLURL:     getStringValue(r.HTMLURL),
-				Language:    getStringValue(r.Language),
-				Stars:       getIntValue(r.StargazersCount),
-				Forks:       getIntValue(r.ForksCount),
-				Owner:       getOwnerLogin(r.Owner),
-			}
+	var allRepos []StarredRepo
+	page := 1
+	for page != 0 {
+		repos, nextPage, err := c.listStarredPage(ctx, username, page, maxPerPage)
+		if err != nil {
+			return nil, err
+		}
+		allRepos = append(allRepos, repos...)
+		page = nextPage
+	}
+	return allRepos, nil
+}
 
-			if r.UpdatedAt != nil {
-				starredRepo.UpdatedAt = r.UpdatedAt.String()
-			}
+// listStarredPage fetches one page of a star list
+func (c *Client) listStarredPage(ctx context.Context, username string, page, perPage int) ([]StarredRepo, int, error) {
+	opts := &github.ActivityListStarredOptions{
+		ListOptions: github.ListOptions{Page: page, PerPage: perPage},
+	}
 
-			allRepos = append(allRepos, starredRepo)
+	var repos []*github.StarredRepository
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		repos, resp, err = c.client.Activity.ListStarred(ctx, username, opts)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	starredRepos := make([]StarredRepo, 0, len(repos))
+	for _, repo := range repos {
+		if repo.Repository == nil {
+			continue
 		}
+		starredRepos = append(starredRepos, fromStarredRepository(repo))
+	}
 
-		if resp.NextPage == 0 {
-			break
+	return starredRepos, resp.NextPage, nil
+}
+
+// GetRepo fetches a single repository by owner and name
+func (c *Client) GetRepo(ctx context.Context, owner, repo string) (*StarredRepo, error) {
+	var r *github.Repository
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		r, resp, err = c.client.Repositories.Get(ctx, owner, repo)
+		return err
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("repository %s/%s %w", owner, repo, ErrNotFound)
 		}
-		opts.Page = resp.NextPage
+		return nil, fmt.Errorf("failed to fetch repository %s/%s: %w", owner, repo, err)
 	}
 
-	return allRepos, nil
+	starredRepo := toStarredRepo(r)
+	return &starredRepo, nil
+}
+
+// IsStarred reports whether the authenticated user has starred a repository
+func (c *Client) IsStarred(ctx context.Context, owner, repo string) (bool, error) {
+	var starred bool
+	err := c.withRetry(ctx, func() error {
+		var err error
+		starred, _, err = c.client.Activity.IsStarred(ctx, owner, repo)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check star status of %s/%s: %w", owner, repo, err)
+	}
+	return starred, nil
+}
+
+// toStarredRepo converts a GitHub API repository to a StarredRepo
+func toStarredRepo(r *github.Repository) StarredRepo {
+	starredRepo := StarredRepo{
+		Name:        getStringValue(r.Name),
+		FullName:    getStringValue(r.FullName),
+		Description: getStringValue(r.Description),
+		URL:         getStringValue(r.URL),
+		HTMLURL:     getStringValue(r.HTMLURL),
+		Language:    getStringValue(r.Language),
+		Stars:       getIntValue(r.StargazersCount),
+		Forks:       getIntValue(r.ForksCount),
+		Owner:       getOwnerLogin(r.Owner),
+		Topics:      r.Topics,
+
+		License:       getLicenseID(r.License),
+		DefaultBranch: getStringValue(r.DefaultBranch),
+		Archived:      getBoolValue(r.Archived),
+		Fork:          getBoolValue(r.Fork),
+		IsTemplate:    getBoolValue(r.IsTemplate),
+		OpenIssues:    getIntValue(r.OpenIssuesCount),
+		Homepage:      getStringValue(r.Homepage),
+		Visibility:    getStringValue(r.Visibility),
+	}
+
+	if r.UpdatedAt != nil {
+		starredRepo.UpdatedAt = r.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	if r.PushedAt != nil {
+		starredRepo.PushedAt = r.PushedAt.UTC().Format(time.RFC3339)
+	}
+
+	return starredRepo
+}
+
+// fromStarredRepository converts a star list entry to a StarredRepo, keeping the star time
+func fromStarredRepository(sr *github.StarredRepository) StarredRepo {
+	starredRepo := toStarredRepo(sr.Repository)
+	if sr.StarredAt != nil {
+		starredRepo.StarredAt = sr.StarredAt.UTC().Format(time.RFC3339)
+	}
+	return starredRepo
 }
 
 // Helper functions to safely extract values from GitHub API responses
@@ -150,6 +422,29 @@ func getIntValue(i *int) int {
 	return *i
 }
 
+func getBoolValue(b *bool) bool {
+	if b == nil {
+		return false
+	}
+	return *b
+}
+
+func getLicenseID(license *github.License) string {
+	if license == nil {
+		return ""
+	}
+	return licenseID(getStringValue(license.SPDXID), getStringValue(license.Name))
+}
+
+// licenseID prefers the SPDX identifier of a license over its name
+func licenseID(spdx, name string) string {
+	// GitHub reports NOASSERTION for licenses it cannot identify
+	if spdx != "" && spdx != "NOASSERTION" {
+		return spdx
+	}
+	return name
+}
+
 func getOwnerLogin(owner *github.User) string {
 	if owner == nil || owner.Login == nil {
 		return ""
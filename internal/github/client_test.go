@@ -1,9 +1,37 @@
 package github
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/go-github/v57/github"
 )
 
+// newTestClient returns a Client that talks to a test server backed by mux
+func newTestClient(t *testing.T, mux *http.ServeMux) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	gh := github.NewClient(nil)
+	baseURL, _ := url.Parse(srv.URL + "/")
+	gh.BaseURL = baseURL
+
+	return &Client{
+		client:     gh,
+		maxRetries: defaultMaxRetries,
+		maxWait:    defaultMaxWait,
+	}
+}
+
 func TestGetStringValue(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -70,7 +98,320 @@ func TestGetIntValue(t *testing.T) {
 	}
 }
 
+func TestGetRepo(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"mcp-go","full_name":"mark3labs/mcp-go","stargazers_count":42,"owner":{"login":"mark3labs"}}`)
+	})
+	client := newTestClient(t, mux)
+
+	repo, err := client.GetRepo(context.Background(), "mark3labs", "mcp-go")
+	if err != nil {
+		t.Fatalf("GetRepo() error = %v", err)
+	}
+	if repo.FullName != "mark3labs/mcp-go" {
+		t.Errorf("FullName = %v, want mark3labs/mcp-go", repo.FullName)
+	}
+	if repo.Owner != "mark3labs" {
+		t.Errorf("Owner = %v, want mark3labs", repo.Owner)
+	}
+	if repo.Stars != 42 {
+		t.Errorf("Stars = %v, want 42", repo.Stars)
+	}
+}
+
+func TestToStarredRepo_Metadata(t *testing.T) {
+	pushed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	r := &github.Repository{
+		FullName:        stringPtr("owner/repo"),
+		License:         &github.License{SPDXID: stringPtr("MIT"), Name: stringPtr("MIT License")},
+		DefaultBranch:   stringPtr("main"),
+		Archived:        boolPtr(true),
+		Fork:            boolPtr(true),
+		IsTemplate:      boolPtr(false),
+		OpenIssuesCount: intPtr(7),
+		PushedAt:        &github.Timestamp{Time: pushed},
+		Homepage:        stringPtr("https://example.com"),
+		Visibility:      stringPtr("public"),
+	}
+
+	repo := toStarredRepo(r)
+
+	if repo.License != "MIT" || repo.DefaultBranch != "main" || repo.Homepage != "https://example.com" || repo.Visibility != "public" {
+		t.Errorf("toStarredRepo() = %+v, want license MIT, branch main, homepage and visibility set", repo)
+	}
+	if !repo.Archived || !repo.Fork || repo.IsTemplate {
+		t.Errorf("flags archived=%v fork=%v template=%v, want true true false", repo.Archived, repo.Fork, repo.IsTemplate)
+	}
+	if repo.OpenIssues != 7 {
+		t.Errorf("OpenIssues = %d, want 7", repo.OpenIssues)
+	}
+	if repo.PushedAt != "2024-05-01T10:00:00Z" {
+		t.Errorf("PushedAt = %q, want 2024-05-01T10:00:00Z", repo.PushedAt)
+	}
+}
+
+func TestGetLicenseID(t *testing.T) {
+	tests := []struct {
+		name     string
+		license  *github.License
+		expected string
+	}{
+		{
+			name:     "nil license",
+			license:  nil,
+			expected: "",
+		},
+		{
+			name:     "SPDX identifier",
+			license:  &github.License{SPDXID: stringPtr("Apache-2.0"), Name: stringPtr("Apache License 2.0")},
+			expected: "Apache-2.0",
+		},
+		{
+			name:     "unrecognized license falls back to name",
+			license:  &github.License{SPDXID: stringPtr("NOASSERTION"), Name: stringPtr("Other")},
+			expected: "Other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := getLicenseID(tt.license); result != tt.expected {
+				t.Errorf("getLicenseID() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetRepo_NotFound(t *testing.T) {
+	client := newTestClient(t, http.NewServeMux())
+
+	_, err := client.GetRepo(context.Background(), "nobody", "nothing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetRepo() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIsStarred(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user/starred/mark3labs/mcp-go", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := newTestClient(t, mux)
+
+	tests := []struct {
+		name     string
+		repo     string
+		expected bool
+	}{
+		{
+			name:     "starred repository",
+			repo:     "mcp-go",
+			expected: true,
+		},
+		{
+			name:     "repository not starred",
+			repo:     "other",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starred, err := client.IsStarred(context.Background(), "mark3labs", tt.repo)
+			if err != nil {
+				t.Fatalf("IsStarred() error = %v", err)
+			}
+			if starred != tt.expected {
+				t.Errorf("IsStarred() = %v, want %v", starred, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetStarredRepos_Pagination(t *testing.T) {
+	mux := http.NewServeMux()
+	var srvURL string
+	mux.HandleFunc("/user/starred", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"repo":{"full_name":"owner/second"}}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%suser/starred?page=2>; rel="next"`, srvURL))
+		fmt.Fprint(w, `[{"repo":{"full_name":"owner/first"}}]`)
+	})
+	client := newTestClient(t, mux)
+	srvURL = client.client.BaseURL.String()
+
+	repos, err := client.GetStarredRepos(context.Background())
+	if err != nil {
+		t.Fatalf("GetStarredRepos() error = %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("len(repos) = %d, want 2", len(repos))
+	}
+	if repos[1].FullName != "owner/second" {
+		t.Errorf("repos[1].FullName = %v, want owner/second", repos[1].FullName)
+	}
+}
+
+func TestGetStarredReposPage(t *testing.T) {
+	mux := http.NewServeMux()
+	var srvURL string
+	mux.HandleFunc("/users/octocat/starred", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("per_page"); got != "2" {
+			t.Errorf("per_page = %q, want 2", got)
+		}
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"repo":{"full_name":"owner/third"}}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%susers/octocat/starred?page=2&per_page=2>; rel="next"`, srvURL))
+		fmt.Fprint(w, `[{"repo":{"full_name":"owner/first"}},{"repo":{"full_name":"owner/second"}}]`)
+	})
+	client := newTestClient(t, mux)
+	srvURL = client.client.BaseURL.String()
+
+	repos, next, err := client.GetStarredReposPage(context.Background(), "octocat", 1, 2)
+	if err != nil {
+		t.Fatalf("GetStarredReposPage() error = %v", err)
+	}
+	if len(repos) != 2 || next != 2 {
+		t.Fatalf("GetStarredReposPage() = %d repos, next page %d; want 2 repos, next page 2", len(repos), next)
+	}
+
+	repos, next, err = client.GetStarredReposPage(context.Background(), "octocat", 2, 2)
+	if err != nil {
+		t.Fatalf("GetStarredReposPage() error = %v", err)
+	}
+	if len(repos) != 1 || next != 0 {
+		t.Errorf("GetStarredReposPage() = %d repos, next page %d; want 1 repo on the last page", len(repos), next)
+	}
+}
+
+func TestGetStarredRepos_StarredAt(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user/starred", func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); !strings.Contains(accept, "star+json") {
+			t.Errorf("Accept = %q, want the star+json media type", accept)
+		}
+		fmt.Fprint(w, `[{"starred_at":"2024-03-15T09:30:00Z","repo":{"full_name":"owner/repo"}}]`)
+	})
+	client := newTestClient(t, mux)
+
+	repos, err := client.GetStarredRepos(context.Background())
+	if err != nil {
+		t.Fatalf("GetStarredRepos() error = %v", err)
+	}
+	if len(repos) != 1 {
+		t.Fatalf("len(repos) = %d, want 1", len(repos))
+	}
+	if repos[0].StarredAt != "2024-03-15T09:30:00Z" {
+		t.Errorf("StarredAt = %q, want 2024-03-15T09:30:00Z", repos[0].StarredAt)
+	}
+}
+
+func TestGetStarredRepos_CancelledContext(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user/starred", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent despite cancelled context")
+	})
+	client := newTestClient(t, mux)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetStarredRepos(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetStarredRepos() error = %v, want context.Canceled", err)
+	}
+}
+
 // Helper functions for tests
+func TestNewClient_EnterpriseURLs(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantHost    string
+		wantBaseURL string
+		wantUpload  string
+		wantErr     bool
+	}{
+		{
+			name:        "github.com",
+			wantHost:    "",
+			wantBaseURL: "https://api.github.com/",
+			wantUpload:  "https://uploads.github.com/",
+		},
+		{
+			name:        "enterprise server",
+			opts:        []Option{WithEnterpriseURLs("https://ghe.example.com", "")},
+			wantHost:    "ghe.example.com",
+			wantBaseURL: "https://ghe.example.com/api/v3/",
+			wantUpload:  "https://ghe.example.com/api/uploads/",
+		},
+		{
+			name:        "separate upload host",
+			opts:        []Option{WithEnterpriseURLs("https://ghe.example.com/api/v3/", "https://uploads.ghe.example.com")},
+			wantHost:    "ghe.example.com",
+			wantBaseURL: "https://ghe.example.com/api/v3/",
+			wantUpload:  "https://uploads.ghe.example.com/api/uploads/",
+		},
+		{
+			name:        "github.com API as base URL",
+			opts:        []Option{WithEnterpriseURLs("https://api.github.com/", "")},
+			wantHost:    "",
+			wantBaseURL: "https://api.github.com/",
+			wantUpload:  "https://api.github.com/",
+		},
+		{
+			name:    "invalid base URL",
+			opts:    []Option{WithEnterpriseURLs("://ghe", "")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(context.Background(), "token", tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if host := client.Host(); host != tt.wantHost {
+				t.Errorf("Host() = %q, want %q", host, tt.wantHost)
+			}
+			if baseURL := client.client.BaseURL.String(); baseURL != tt.wantBaseURL {
+				t.Errorf("BaseURL = %q, want %q", baseURL, tt.wantBaseURL)
+			}
+			if uploadURL := client.client.UploadURL.String(); uploadURL != tt.wantUpload {
+				t.Errorf("UploadURL = %q, want %q", uploadURL, tt.wantUpload)
+			}
+		})
+	}
+}
+
+// TestHostOf tests that configured base URLs map to the host the client reports
+func TestHostOf(t *testing.T) {
+	tests := map[string]string{
+		"":                                 "",
+		"https://api.github.com":           "",
+		"https://api.github.com/":          "",
+		"https://ghe.example.com":          "ghe.example.com",
+		"https://ghe.example.com/api/v3/":  "ghe.example.com",
+		"https://ghe.example.com:8443/api": "ghe.example.com:8443",
+	}
+
+	for baseURL, want := range tests {
+		if host := HostOf(baseURL); host != want {
+			t.Errorf("HostOf(%q) = %q, want %q", baseURL, host, want)
+		}
+	}
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -78,3 +419,7 @@ func stringPtr(s string) *string {
 func intPtr(i int) *int {
 	return &i
 }
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,107 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Tree is the recursive file listing of a repository at a ref
+type Tree struct {
+	SHA string
+
+	// Truncated is set when GitHub cut the listing short for very large repositories
+	Truncated bool
+
+	Entries []TreeEntry
+}
+
+// TreeEntry is a single path in a Tree
+type TreeEntry struct {
+	Path string
+	Type string // "blob", "tree" or "commit" (submodule)
+	Size int
+}
+
+// FileContent is a file fetched from a repository
+type FileContent struct {
+	Path string
+	Size int
+
+	// Content is nil when the file is too large for the contents API to inline
+	Content []byte
+}
+
+// GetTree fetches the recursive tree of a repository at ref, which may be a
+// branch, tag or commit SHA
+func (c *Client) GetTree(ctx context.Context, owner, repo, ref string) (*Tree, error) {
+	var tree *github.Tree
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		tree, resp, err = c.client.Git.GetTree(ctx, owner, repo, ref, true)
+		return err
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("tree %s of %s/%s %w", ref, owner, repo, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch tree %s of %s/%s: %w", ref, owner, repo, err)
+	}
+
+	result := &Tree{
+		SHA:       tree.GetSHA(),
+		Truncated: tree.GetTruncated(),
+		Entries:   make([]TreeEntry, 0, len(tree.Entries)),
+	}
+	for _, entry := range tree.Entries {
+		result.Entries = append(result.Entries, TreeEntry{
+			Path: entry.GetPath(),
+			Type: entry.GetType(),
+			Size: entry.GetSize(),
+		})
+	}
+
+	return result, nil
+}
+
+// GetFileContent fetches a file of a repository at ref. Directories,
+// symlinks and submodules are reported as ErrNotFound.
+func (c *Client) GetFileContent(ctx context.Context, owner, repo, ref, path string) (*FileContent, error) {
+	var content *github.RepositoryContent
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		content, _, resp, err = c.client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+		return err
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("file %s at %s of %s/%s %w", path, ref, owner, repo, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to fetch file %s at %s of %s/%s: %w", path, ref, owner, repo, err)
+	}
+	if content == nil || content.GetType() != "file" {
+		return nil, fmt.Errorf("file %s at %s of %s/%s %w", path, ref, owner, repo, ErrNotFound)
+	}
+
+	file := &FileContent{
+		Path: content.GetPath(),
+		Size: content.GetSize(),
+	}
+
+	// Files over 1 MB come back without inline content ("none" encoding)
+	if content.GetEncoding() == "none" {
+		return file, nil
+	}
+
+	text, err := content.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode file %s at %s of %s/%s: %w", path, ref, owner, repo, err)
+	}
+	file.Content = []byte(text)
+
+	return file, nil
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetTree(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go/git/trees/main", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("recursive") == "" {
+			t.Errorf("GetTree() did not request a recursive tree")
+		}
+		fmt.Fprint(w, `{"sha":"abc123","truncated":true,"tree":[
+			{"path":"README.md","type":"blob","size":42},
+			{"path":"mcp","type":"tree"},
+			{"path":"mcp/tools.go","type":"blob","size":1024}
+		]}`)
+	})
+	client := newTestClient(t, mux)
+
+	tree, err := client.GetTree(context.Background(), "mark3labs", "mcp-go", "main")
+	if err != nil {
+		t.Fatalf("GetTree() error = %v", err)
+	}
+	if tree.SHA != "abc123" || !tree.Truncated {
+		t.Errorf("GetTree() = sha %q truncated %v, want abc123 truncated", tree.SHA, tree.Truncated)
+	}
+	if len(tree.Entries) != 3 {
+		t.Fatalf("GetTree() returned %d entries, want 3", len(tree.Entries))
+	}
+	want := TreeEntry{Path: "mcp/tools.go", Type: "blob", Size: 1024}
+	if tree.Entries[2] != want {
+		t.Errorf("GetTree() entry = %+v, want %+v", tree.Entries[2], want)
+	}
+}
+
+func TestGetFileContent(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go/contents/go.mod", func(w http.ResponseWriter, r *http.Request) {
+		if ref := r.URL.Query().Get("ref"); ref != "v1.0.0" {
+			t.Errorf("GetFileContent() ref = %q, want v1.0.0", ref)
+		}
+		// "module x\n" base64-encoded
+		fmt.Fprint(w, `{"type":"file","encoding":"base64","path":"go.mod","size":9,"content":"bW9kdWxlIHgK"}`)
+	})
+	mux.HandleFunc("/repos/mark3labs/mcp-go/contents/big.bin", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"type":"file","encoding":"none","path":"big.bin","size":5000000,"content":""}`)
+	})
+	mux.HandleFunc("/repos/mark3labs/mcp-go/contents/mcp", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"type":"file","path":"mcp/tools.go"}]`)
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	file, err := client.GetFileContent(ctx, "mark3labs", "mcp-go", "v1.0.0", "go.mod")
+	if err != nil {
+		t.Fatalf("GetFileContent() error = %v", err)
+	}
+	if string(file.Content) != "module x\n" || file.Size != 9 {
+		t.Errorf("GetFileContent() = %q (%d bytes), want %q", file.Content, file.Size, "module x\n")
+	}
+
+	file, err = client.GetFileContent(ctx, "mark3labs", "mcp-go", "v1.0.0", "big.bin")
+	if err != nil {
+		t.Fatalf("GetFileContent() large file error = %v", err)
+	}
+	if file.Content != nil || file.Size != 5000000 {
+		t.Errorf("GetFileContent() large file = %d bytes inline, size %d; want no content, size 5000000", len(file.Content), file.Size)
+	}
+
+	if _, err := client.GetFileContent(ctx, "mark3labs", "mcp-go", "v1.0.0", "mcp"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetFileContent() on directory error = %v, want ErrNotFound", err)
+	}
+	if _, err := client.GetFileContent(ctx, "mark3labs", "mcp-go", "v1.0.0", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetFileContent() on missing file error = %v, want ErrNotFound", err)
+	}
+}
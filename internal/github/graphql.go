@@ -0,0 +1,79 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// graphQLPath locates the GraphQL endpoint relative to the REST base URL:
+// https://api.github.com/ resolves to https://api.github.com/graphql and a
+// GitHub Enterprise Server's /api/v3/ to /api/graphql
+const graphQLPath = "../graphql"
+
+// graphQLRequest is the body of a GraphQL API call
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLResponse is the envelope of a GraphQL API response
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+// graphQLError is an error reported in a GraphQL response body
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// pageInfo is the GraphQL connection pagination state
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// graphQL runs a GraphQL query and decodes its data into result. Errors
+// reported with a NOT_FOUND type wrap ErrNotFound.
+func (c *Client) graphQL(ctx context.Context, query string, variables map[string]any, result any) error {
+	var resp graphQLResponse
+	err := c.withRetry(ctx, func() error {
+		// The request body is consumed on send, so each attempt needs a new request
+		req, err := c.client.NewRequest(http.MethodPost, graphQLPath, graphQLRequest{
+			Query:     query,
+			Variables: variables,
+		})
+		if err != nil {
+			return err
+		}
+
+		resp = graphQLResponse{}
+		_, err = c.client.Do(ctx, req, &resp)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run GraphQL query: %w", err)
+	}
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		notFound := false
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+			notFound = notFound || e.Type == "NOT_FOUND"
+		}
+		if notFound {
+			return fmt.Errorf("GraphQL query failed: %s: %w", strings.Join(messages, "; "), ErrNotFound)
+		}
+		return fmt.Errorf("GraphQL query failed: %s", strings.Join(messages, "; "))
+	}
+
+	if err := json.Unmarshal(resp.Data, result); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// StarList is one of the authenticated user's star lists
+type StarList struct {
+	ID          string
+	Name        string
+	Slug        string
+	Description string
+	IsPrivate   bool
+	UpdatedAt   string // RFC 3339 UTC
+
+	// Repositories are the full names of the list's repositories, in list order
+	Repositories []string
+}
+
+// starListsKey caches the result of GetStarLists. It cannot collide with the
+// "stars:{username}" keys of listAllStarredGraphQL.
+const starListsKey = "lists"
+
+// starListsQuery reads the viewer's star lists along with the first page of
+// each list's repositories
+const starListsQuery = `query($cursor: String) {
+  viewer {
+    lists(first: 100, after: $cursor) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        id
+        name
+        slug
+        description
+        isPrivate
+        updatedAt
+        items(first: 100) {
+          pageInfo { hasNextPage endCursor }
+          nodes { ... on Repository { nameWithOwner } }
+        }
+      }
+    }
+  }
+}`
+
+// starListItemsQuery reads a further page of a star list's repositories
+const starListItemsQuery = `query($id: ID!, $cursor: String) {
+  node(id: $id) {
+    ... on UserList {
+      items(first: 100, after: $cursor) {
+        pageInfo { hasNextPage endCursor }
+        nodes { ... on Repository { nameWithOwner } }
+      }
+    }
+  }
+}`
+
+// starListItems is a page of repositories in a star list
+type starListItems struct {
+	PageInfo pageInfo `json:"pageInfo"`
+	Nodes    []struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"nodes"`
+}
+
+// GetStarLists fetches the authenticated user's star lists and their
+// repositories. With the response cache enabled, the lists are kept in memory
+// for its TTL, since every repository resource names the lists it is in.
+func (c *Client) GetStarLists(ctx context.Context) ([]StarList, error) {
+	if cached, ok := c.results.get(starListsKey); ok {
+		return slices.Clone(cached.([]StarList)), nil
+	}
+
+	var lists []StarList
+	var cursor *string
+
+	for {
+		var data struct {
+			Viewer struct {
+				Lists struct {
+					PageInfo pageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						ID          string        `json:"id"`
+						Name        string        `json:"name"`
+						Slug        string        `json:"slug"`
+						Description string        `json:"description"`
+						IsPrivate   bool          `json:"isPrivate"`
+						UpdatedAt   time.Time     `json:"updatedAt"`
+						Items       starListItems `json:"items"`
+					} `json:"nodes"`
+				} `json:"lists"`
+			} `json:"viewer"`
+		}
+		if err := c.graphQL(ctx, starListsQuery, map[string]any{"cursor": cursor}, &data); err != nil {
+			return nil, fmt.Errorf("failed to fetch star lists: %w", err)
+		}
+
+		for _, node := range data.Viewer.Lists.Nodes {
+			list := StarList{
+				ID:          node.ID,
+				Name:        node.Name,
+				Slug:        node.Slug,
+				Description: node.Description,
+				IsPrivate:   node.IsPrivate,
+				UpdatedAt:   node.UpdatedAt.UTC().Format(time.RFC3339),
+			}
+
+			items := node.Items
+			for {
+				for _, item := range items.Nodes {
+					if item.NameWithOwner != "" {
+						list.Repositories = append(list.Repositories, item.NameWithOwner)
+					}
+				}
+				if !items.PageInfo.HasNextPage {
+					break
+				}
+
+				next, err := c.getStarListItems(ctx, list.ID, items.PageInfo.EndCursor)
+				if err != nil {
+					return nil, fmt.Errorf("failed to fetch star list %s: %w", list.Name, err)
+				}
+				items = *next
+			}
+
+			lists = append(lists, list)
+		}
+
+		if !data.Viewer.Lists.PageInfo.HasNextPage {
+			c.results.put(starListsKey, slices.Clone(lists))
+			return lists, nil
+		}
+		endCursor := data.Viewer.Lists.PageInfo.EndCursor
+		cursor = &endCursor
+	}
+}
+
+// getStarListItems fetches the page of a star list's repositories after cursor
+func (c *Client) getStarListItems(ctx context.Context, id, cursor string) (*starListItems, error) {
+	var data struct {
+		Node struct {
+			Items starListItems `json:"items"`
+		} `json:"node"`
+	}
+	if err := c.graphQL(ctx, starListItemsQuery, map[string]any{"id": id, "cursor": cursor}, &data); err != nil {
+		return nil, err
+	}
+	return &data.Node.Items, nil
+}
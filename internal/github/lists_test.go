@@ -0,0 +1,140 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetStarLists(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("GraphQL request method = %s, want POST", r.Method)
+		}
+
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode GraphQL request: %v", err)
+		}
+
+		switch {
+		case strings.Contains(req.Query, "viewer") && req.Variables["cursor"] == nil:
+			fmt.Fprint(w, `{"data":{"viewer":{"lists":{
+				"pageInfo":{"hasNextPage":true,"endCursor":"lists-1"},
+				"nodes":[{"id":"UL_1","name":"Go tools","slug":"go-tools","description":"CLIs","isPrivate":false,
+					"updatedAt":"2024-06-01T12:00:00+02:00",
+					"items":{"pageInfo":{"hasNextPage":true,"endCursor":"items-1"},"nodes":[{"nameWithOwner":"spf13/cobra"}]}}]}}}}`)
+		case strings.Contains(req.Query, "viewer"):
+			fmt.Fprint(w, `{"data":{"viewer":{"lists":{
+				"pageInfo":{"hasNextPage":false},
+				"nodes":[{"id":"UL_2","name":"Empty","slug":"empty","isPrivate":true,"updatedAt":"2024-01-01T00:00:00Z",
+					"items":{"pageInfo":{"hasNextPage":false},"nodes":[]}}]}}}}`)
+		case req.Variables["id"] == "UL_1" && req.Variables["cursor"] == "items-1":
+			fmt.Fprint(w, `{"data":{"node":{"items":{"pageInfo":{"hasNextPage":false},"nodes":[{"nameWithOwner":"urfave/cli"}]}}}}`)
+		default:
+			t.Errorf("unexpected GraphQL request: %+v", req)
+		}
+	})
+	client := newTestClient(t, mux)
+
+	lists, err := client.GetStarLists(context.Background())
+	if err != nil {
+		t.Fatalf("GetStarLists() error = %v", err)
+	}
+	if len(lists) != 2 {
+		t.Fatalf("GetStarLists() returned %d lists, want 2", len(lists))
+	}
+
+	first := lists[0]
+	if first.Name != "Go tools" || first.Slug != "go-tools" || first.Description != "CLIs" || first.IsPrivate {
+		t.Errorf("first list = %+v, want public Go tools list", first)
+	}
+	if first.UpdatedAt != "2024-06-01T10:00:00Z" {
+		t.Errorf("UpdatedAt = %q, want 2024-06-01T10:00:00Z", first.UpdatedAt)
+	}
+	if strings.Join(first.Repositories, ",") != "spf13/cobra,urfave/cli" {
+		t.Errorf("Repositories = %v, want both pages of items", first.Repositories)
+	}
+	if !lists[1].IsPrivate || len(lists[1].Repositories) != 0 {
+		t.Errorf("second list = %+v, want an empty private list", lists[1])
+	}
+}
+
+func TestGetStarLists_Cached(t *testing.T) {
+	var queries int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/graphql", func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		fmt.Fprint(w, `{"data":{"viewer":{"lists":{"pageInfo":{"hasNextPage":false},
+			"nodes":[{"id":"UL_1","name":"Go tools","slug":"go-tools","updatedAt":"2024-01-01T00:00:00Z",
+				"items":{"pageInfo":{"hasNextPage":false},"nodes":[{"nameWithOwner":"spf13/cobra"}]}}]}}}}`)
+	})
+	mux.HandleFunc("/api/v3/user/starred/o/r", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewClient(context.Background(), "token",
+		WithEnterpriseURLs(srv.URL, ""), WithCache(t.TempDir(), time.Hour), WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		lists, err := client.GetStarLists(ctx)
+		if err != nil {
+			t.Fatalf("GetStarLists() error = %v", err)
+		}
+		if len(lists) != 1 || lists[0].Slug != "go-tools" {
+			t.Fatalf("GetStarLists() = %+v, want the go-tools list", lists)
+		}
+	}
+	if queries != 1 {
+		t.Errorf("GraphQL queries = %d, want 1 while the lists are cached", queries)
+	}
+
+	// A write makes the cached lists stale
+	if err := client.StarRepo(ctx, "o", "r"); err != nil {
+		t.Fatalf("StarRepo() error = %v", err)
+	}
+	if _, err := client.GetStarLists(ctx); err != nil {
+		t.Fatalf("GetStarLists() error = %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("GraphQL queries = %d, want 2 after a write", queries)
+	}
+}
+
+func TestGraphQL_Errors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["id"] == "missing" {
+			fmt.Fprint(w, `{"data":{"node":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a node"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"errors":[{"message":"Field 'lists' doesn't exist"}]}`)
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	var data struct{}
+	if err := client.graphQL(ctx, "query { node }", map[string]any{"id": "missing"}, &data); !errors.Is(err, ErrNotFound) {
+		t.Errorf("graphQL() error = %v, want ErrNotFound", err)
+	}
+
+	err := client.graphQL(ctx, "query { viewer }", nil, &data)
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "doesn't exist") {
+		t.Errorf("graphQL() error = %v, want the reported message", err)
+	}
+}
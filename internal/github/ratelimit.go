@@ -0,0 +1,160 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Default retry behaviour for rate-limited requests
+const (
+	defaultMaxRetries = 3
+	defaultMaxWait    = time.Minute
+	baseRetryDelay    = time.Second
+)
+
+// RateLimit describes the API quota for one GitHub rate limit category
+type RateLimit struct {
+	Resource  string
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// GetRateLimits fetches the current rate limit status. Querying the rate
+// limit does not count against the quota.
+func (c *Client) GetRateLimits(ctx context.Context) ([]RateLimit, error) {
+	limits, _, err := c.client.RateLimit.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rate limits: %w", err)
+	}
+
+	categories := []struct {
+		name string
+		rate *github.Rate
+	}{
+		{"core", limits.Core},
+		{"search", limits.Search},
+		{"graphql", limits.GraphQL},
+	}
+
+	rateLimits := make([]RateLimit, 0, len(categories))
+	for _, category := range categories {
+		if category.rate == nil {
+			continue
+		}
+		rateLimits = append(rateLimits, RateLimit{
+			Resource:  category.name,
+			Limit:     category.rate.Limit,
+			Remaining: category.rate.Remaining,
+			Reset:     category.rate.Reset.Time,
+		})
+	}
+
+	return rateLimits, nil
+}
+
+// withRetry runs call, sleeping and retrying when GitHub reports a primary
+// or secondary rate limit. Other errors are returned immediately, as is the
+// original error when ctx would expire before the retry.
+func (c *Client) withRetry(ctx context.Context, call func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := call()
+		if err == nil {
+			return nil
+		}
+
+		wait, ok := c.retryDelay(err, attempt)
+		if !ok || attempt >= c.maxRetries {
+			return err
+		}
+		if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Until(deadline) < wait {
+			return err
+		}
+
+		log.Printf("GitHub rate limit hit, retrying in %v (attempt %d of %d)", wait, attempt+1, c.maxRetries)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay reports how long to wait before retrying a failed request, and
+// whether the failure is a rate limit worth retrying at all
+func (c *Client) retryDelay(err error, attempt int) (time.Duration, bool) {
+	var wait time.Duration
+
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	var errResp *github.ErrorResponse
+
+	switch {
+	case errors.As(err, &rateErr):
+		// Primary rate limit: nothing succeeds until the window resets
+		wait = time.Until(rateErr.Rate.Reset.Time) + time.Second
+	case errors.As(err, &abuseErr):
+		if abuseErr.RetryAfter != nil {
+			wait = *abuseErr.RetryAfter
+		} else {
+			wait = backoff(attempt)
+		}
+	case errors.As(err, &errResp) && isRetryableStatus(errResp.Response):
+		if retryAfter, ok := parseRetryAfter(errResp.Response.Header.Get("Retry-After")); ok {
+			wait = retryAfter
+		} else {
+			wait = backoff(attempt)
+		}
+	default:
+		return 0, false
+	}
+
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > c.maxWait {
+		// Waiting this long would stall the MCP request; surface the error instead
+		return 0, false
+	}
+
+	return wait, true
+}
+
+// isRetryableStatus reports whether a response is a rate limit that go-github
+// does not classify itself: a 429, or a 403 carrying Retry-After
+func isRetryableStatus(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("Retry-After") != "")
+}
+
+// backoff returns an exponential delay for the given attempt
+func backoff(attempt int) time.Duration {
+	return baseRetryDelay << attempt
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
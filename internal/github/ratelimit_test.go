@@ -0,0 +1,178 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{
+			name:     "seconds",
+			value:    "30",
+			expected: 30 * time.Second,
+			ok:       true,
+		},
+		{
+			name:  "empty",
+			value: "",
+			ok:    false,
+		},
+		{
+			name:  "garbage",
+			value: "soon",
+			ok:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := parseRetryAfter(tt.value)
+			if ok != tt.ok {
+				t.Fatalf("parseRetryAfter(%q) ok = %v, want %v", tt.value, ok, tt.ok)
+			}
+			if result != tt.expected {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWithRetry_SecondaryRateLimit(t *testing.T) {
+	var hits int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit"}`)
+			return
+		}
+		fmt.Fprint(w, `{"name":"mcp-go","full_name":"mark3labs/mcp-go"}`)
+	})
+	client := newTestClient(t, mux)
+
+	repo, err := client.GetRepo(context.Background(), "mark3labs", "mcp-go")
+	if err != nil {
+		t.Fatalf("GetRepo() error = %v", err)
+	}
+	if repo.FullName != "mark3labs/mcp-go" {
+		t.Errorf("FullName = %v, want mark3labs/mcp-go", repo.FullName)
+	}
+	if hits != 2 {
+		t.Errorf("server hits = %d, want 2", hits)
+	}
+}
+
+func TestWithRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	var hits int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	client := newTestClient(t, mux)
+	client.maxRetries = 2
+
+	if _, err := client.GetRepo(context.Background(), "mark3labs", "mcp-go"); err == nil {
+		t.Fatal("GetRepo() expected error after exhausting retries, got nil")
+	}
+	if hits != 3 {
+		t.Errorf("server hits = %d, want 3 (initial attempt + 2 retries)", hits)
+	}
+}
+
+func TestWithRetry_WaitTooLong(t *testing.T) {
+	var hits int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	client := newTestClient(t, mux)
+
+	if _, err := client.GetRepo(context.Background(), "mark3labs", "mcp-go"); err == nil {
+		t.Fatal("GetRepo() expected error when Retry-After exceeds max wait, got nil")
+	}
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1", hits)
+	}
+}
+
+func TestWithRetry_DeadlineBeforeRetry(t *testing.T) {
+	var hits int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	client := newTestClient(t, mux)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.GetRepo(ctx, "mark3labs", "mcp-go"); err == nil {
+		t.Fatal("GetRepo() expected error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetRepo() took %v, want immediate failure when the deadline precedes the retry", elapsed)
+	}
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1", hits)
+	}
+}
+
+func TestWithRetry_NonRateLimitError(t *testing.T) {
+	var hits int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	client := newTestClient(t, mux)
+
+	if _, err := client.GetRepo(context.Background(), "mark3labs", "mcp-go"); err == nil {
+		t.Fatal("GetRepo() expected error, got nil")
+	}
+	if hits != 1 {
+		t.Errorf("server hits = %d, want 1 (no retry)", hits)
+	}
+}
+
+func TestGetRateLimits(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rate_limit", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"resources":{
+			"core":{"limit":5000,"remaining":4990,"reset":1700000000},
+			"search":{"limit":30,"remaining":30,"reset":1700000000}
+		}}`)
+	})
+	client := newTestClient(t, mux)
+
+	limits, err := client.GetRateLimits(context.Background())
+	if err != nil {
+		t.Fatalf("GetRateLimits() error = %v", err)
+	}
+	if len(limits) != 2 {
+		t.Fatalf("len(limits) = %d, want 2", len(limits))
+	}
+	if limits[0].Resource != "core" || limits[0].Remaining != 4990 {
+		t.Errorf("limits[0] = %+v, want core with 4990 remaining", limits[0])
+	}
+	if !limits[0].Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("limits[0].Reset = %v, want %v", limits[0].Reset, time.Unix(1700000000, 0))
+	}
+}
@@ -0,0 +1,33 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// GetReadme fetches the raw README text of a repository
+func (c *Client) GetReadme(ctx context.Context, owner, repo string) (string, error) {
+	var content *github.RepositoryContent
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		content, resp, err = c.client.Repositories.GetReadme(ctx, owner, repo, nil)
+		return err
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", fmt.Errorf("README for %s/%s %w", owner, repo, ErrNotFound)
+		}
+		return "", fmt.Errorf("failed to fetch README for %s/%s: %w", owner, repo, err)
+	}
+
+	text, err := content.GetContent()
+	if err != nil {
+		return "", fmt.Errorf("failed to decode README for %s/%s: %w", owner, repo, err)
+	}
+
+	return text, nil
+}
@@ -0,0 +1,35 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetReadme(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go/readme", func(w http.ResponseWriter, r *http.Request) {
+		// "# mcp-go\n" base64-encoded, as returned by the contents API
+		fmt.Fprint(w, `{"type":"file","encoding":"base64","name":"README.md","content":"IyBtY3AtZ28K"}`)
+	})
+	client := newTestClient(t, mux)
+
+	readme, err := client.GetReadme(context.Background(), "mark3labs", "mcp-go")
+	if err != nil {
+		t.Fatalf("GetReadme() error = %v", err)
+	}
+	if readme != "# mcp-go\n" {
+		t.Errorf("GetReadme() = %q, want %q", readme, "# mcp-go\n")
+	}
+}
+
+func TestGetReadme_NotFound(t *testing.T) {
+	client := newTestClient(t, http.NewServeMux())
+
+	_, err := client.GetReadme(context.Background(), "nobody", "nothing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetReadme() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,93 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Release is a published release of a repository
+type Release struct {
+	TagName     string
+	Name        string
+	Body        string
+	HTMLURL     string
+	PublishedAt string // RFC 3339, empty for drafts
+	Prerelease  bool
+	Draft       bool
+}
+
+// Tag is a git tag of a repository
+type Tag struct {
+	Name      string
+	CommitSHA string
+}
+
+// GetReleases fetches up to limit of the most recent releases of a repository, newest first
+func (c *Client) GetReleases(ctx context.Context, owner, repo string, limit int) ([]Release, error) {
+	opts := &github.ListOptions{PerPage: min(limit, maxPerPage)}
+
+	var releases []*github.RepositoryRelease
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		releases, resp, err = c.client.Repositories.ListReleases(ctx, owner, repo, opts)
+		return err
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("releases of %s/%s %w", owner, repo, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to list releases of %s/%s: %w", owner, repo, err)
+	}
+
+	result := make([]Release, 0, len(releases))
+	for _, r := range releases {
+		release := Release{
+			TagName:    r.GetTagName(),
+			Name:       r.GetName(),
+			Body:       r.GetBody(),
+			HTMLURL:    r.GetHTMLURL(),
+			Prerelease: r.GetPrerelease(),
+			Draft:      r.GetDraft(),
+		}
+		if r.PublishedAt != nil {
+			release.PublishedAt = r.PublishedAt.UTC().Format(time.RFC3339)
+		}
+		result = append(result, release)
+	}
+
+	return result, nil
+}
+
+// GetTags fetches up to limit tags of a repository, in the order GitHub returns them
+func (c *Client) GetTags(ctx context.Context, owner, repo string, limit int) ([]Tag, error) {
+	opts := &github.ListOptions{PerPage: min(limit, maxPerPage)}
+
+	var tags []*github.RepositoryTag
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		tags, resp, err = c.client.Repositories.ListTags(ctx, owner, repo, opts)
+		return err
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("tags of %s/%s %w", owner, repo, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to list tags of %s/%s: %w", owner, repo, err)
+	}
+
+	result := make([]Tag, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, Tag{
+			Name:      t.GetName(),
+			CommitSHA: t.GetCommit().GetSHA(),
+		})
+	}
+
+	return result, nil
+}
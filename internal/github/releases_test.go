@@ -0,0 +1,66 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetReleases(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go/releases", func(w http.ResponseWriter, r *http.Request) {
+		if perPage := r.URL.Query().Get("per_page"); perPage != "10" {
+			t.Errorf("GetReleases() per_page = %q, want 10", perPage)
+		}
+		fmt.Fprint(w, `[
+			{"tag_name":"v0.2.0-rc1","name":"v0.2.0 RC 1","body":"notes","html_url":"https://github.com/mark3labs/mcp-go/releases/tag/v0.2.0-rc1","prerelease":true,"published_at":"2024-06-01T12:00:00+02:00"},
+			{"tag_name":"v0.3.0","draft":true}
+		]`)
+	})
+	client := newTestClient(t, mux)
+
+	releases, err := client.GetReleases(context.Background(), "mark3labs", "mcp-go", 10)
+	if err != nil {
+		t.Fatalf("GetReleases() error = %v", err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("GetReleases() returned %d releases, want 2", len(releases))
+	}
+
+	want := Release{
+		TagName:     "v0.2.0-rc1",
+		Name:        "v0.2.0 RC 1",
+		Body:        "notes",
+		HTMLURL:     "https://github.com/mark3labs/mcp-go/releases/tag/v0.2.0-rc1",
+		PublishedAt: "2024-06-01T10:00:00Z",
+		Prerelease:  true,
+	}
+	if releases[0] != want {
+		t.Errorf("GetReleases()[0] = %+v, want %+v", releases[0], want)
+	}
+	if !releases[1].Draft || releases[1].PublishedAt != "" {
+		t.Errorf("GetReleases()[1] = %+v, want an unpublished draft", releases[1])
+	}
+
+	if _, err := client.GetReleases(context.Background(), "nobody", "nothing", 10); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetReleases() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetTags(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/mark3labs/mcp-go/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name":"v0.1.0","commit":{"sha":"abc123"}}]`)
+	})
+	client := newTestClient(t, mux)
+
+	tags, err := client.GetTags(context.Background(), "mark3labs", "mcp-go", 30)
+	if err != nil {
+		t.Fatalf("GetTags() error = %v", err)
+	}
+	if len(tags) != 1 || tags[0] != (Tag{Name: "v0.1.0", CommitSHA: "abc123"}) {
+		t.Errorf("GetTags() = %+v, want [{v0.1.0 abc123}]", tags)
+	}
+}
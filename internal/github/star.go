@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// StarRepo stars a repository for the authenticated user. Starring an
+// already starred repository succeeds.
+func (c *Client) StarRepo(ctx context.Context, owner, repo string) error {
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		resp, err = c.client.Activity.Star(ctx, owner, repo)
+		return err
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("repository %s/%s %w", owner, repo, ErrNotFound)
+		}
+		return fmt.Errorf("failed to star %s/%s: %w", owner, repo, err)
+	}
+	return nil
+}
+
+// UnstarRepo removes the authenticated user's star from a repository.
+// Unstarring a repository that is not starred succeeds.
+func (c *Client) UnstarRepo(ctx context.Context, owner, repo string) error {
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		resp, err = c.client.Activity.Unstar(ctx, owner, repo)
+		return err
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("repository %s/%s %w", owner, repo, ErrNotFound)
+		}
+		return fmt.Errorf("failed to unstar %s/%s: %w", owner, repo, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestStarRepo(t *testing.T) {
+	var starred, unstarred bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user/starred/mark3labs/mcp-go", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			starred = true
+		case http.MethodDelete:
+			unstarred = true
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	client := newTestClient(t, mux)
+	ctx := context.Background()
+
+	if err := client.StarRepo(ctx, "mark3labs", "mcp-go"); err != nil {
+		t.Fatalf("StarRepo() error = %v", err)
+	}
+	if err := client.UnstarRepo(ctx, "mark3labs", "mcp-go"); err != nil {
+		t.Fatalf("UnstarRepo() error = %v", err)
+	}
+	if !starred || !unstarred {
+		t.Errorf("starred = %v, unstarred = %v, want both", starred, unstarred)
+	}
+
+	if err := client.StarRepo(ctx, "nobody", "nothing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("StarRepo() error = %v, want ErrNotFound", err)
+	}
+	if err := client.UnstarRepo(ctx, "nobody", "nothing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UnstarRepo() error = %v, want ErrNotFound", err)
+	}
+}
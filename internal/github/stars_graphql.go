@@ -0,0 +1,192 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// starredRepositoriesFields selects a page of a star list with every field of
+// StarredRepo, so the whole star list takes one request per 100 stars
+const starredRepositoriesFields = `starredRepositories(first: 100, after: $cursor, orderBy: {field: STARRED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      edges {
+        starredAt
+        node {
+          name
+          nameWithOwner
+          description
+          url
+          homepageUrl
+          owner { login }
+          primaryLanguage { name }
+          stargazerCount
+          forkCount
+          updatedAt
+          pushedAt
+          repositoryTopics(first: 20) { nodes { topic { name } } }
+          licenseInfo { spdxId name }
+          defaultBranchRef { name }
+          isArchived
+          isFork
+          isTemplate
+          visibility
+          issues(states: OPEN) { totalCount }
+          pullRequests(states: OPEN) { totalCount }
+        }
+      }
+    }`
+
+// viewerStarsQuery reads a page of the authenticated user's star list
+const viewerStarsQuery = `query($cursor: String) {
+  user: viewer {
+    ` + starredRepositoriesFields + `
+  }
+}`
+
+// userStarsQuery reads a page of a user's star list
+const userStarsQuery = `query($login: String!, $cursor: String) {
+  user(login: $login) {
+    ` + starredRepositoriesFields + `
+  }
+}`
+
+// starredRepositoryNode is a repository as returned by starredRepositoriesFields
+type starredRepositoryNode struct {
+	Name          string `json:"name"`
+	NameWithOwner string `json:"nameWithOwner"`
+	Description   string `json:"description"`
+	URL           string `json:"url"`
+	HomepageURL   string `json:"homepageUrl"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	StargazerCount   int        `json:"stargazerCount"`
+	ForkCount        int        `json:"forkCount"`
+	UpdatedAt        *time.Time `json:"updatedAt"`
+	PushedAt         *time.Time `json:"pushedAt"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	LicenseInfo *struct {
+		SPDXID string `json:"spdxId"`
+		Name   string `json:"name"`
+	} `json:"licenseInfo"`
+	DefaultBranchRef *struct {
+		Name string `json:"name"`
+	} `json:"defaultBranchRef"`
+	IsArchived bool   `json:"isArchived"`
+	IsFork     bool   `json:"isFork"`
+	IsTemplate bool   `json:"isTemplate"`
+	Visibility string `json:"visibility"`
+	Issues     struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"issues"`
+	PullRequests struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"pullRequests"`
+}
+
+// listAllStarredGraphQL reads a whole star list through the GraphQL API. An
+// empty username lists the authenticated user's stars. With the response
+// cache enabled, the list is kept in memory for its TTL.
+func (c *Client) listAllStarredGraphQL(ctx context.Context, username string) ([]StarredRepo, error) {
+	key := "stars:" + username
+	if cached, ok := c.results.get(key); ok {
+		return slices.Clone(cached.([]StarredRepo)), nil
+	}
+
+	query := viewerStarsQuery
+	variables := map[string]any{"cursor": nil}
+	if username != "" {
+		query = userStarsQuery
+		variables["login"] = username
+	}
+
+	var allRepos []StarredRepo
+	for {
+		var data struct {
+			User *struct {
+				StarredRepositories struct {
+					PageInfo pageInfo `json:"pageInfo"`
+					Edges    []struct {
+						StarredAt time.Time             `json:"starredAt"`
+						Node      starredRepositoryNode `json:"node"`
+					} `json:"edges"`
+				} `json:"starredRepositories"`
+			} `json:"user"`
+		}
+		if err := c.graphQL(ctx, query, variables, &data); err != nil {
+			return nil, err
+		}
+		if data.User == nil {
+			return nil, fmt.Errorf("user %s %w", username, ErrNotFound)
+		}
+
+		stars := data.User.StarredRepositories
+		for _, edge := range stars.Edges {
+			repo := c.fromRepositoryNode(edge.Node)
+			repo.StarredAt = edge.StarredAt.UTC().Format(time.RFC3339)
+			allRepos = append(allRepos, repo)
+		}
+
+		if !stars.PageInfo.HasNextPage {
+			c.results.put(key, slices.Clone(allRepos))
+			return allRepos, nil
+		}
+		variables["cursor"] = stars.PageInfo.EndCursor
+	}
+}
+
+// fromRepositoryNode converts a GraphQL repository to a StarredRepo with the
+// same values the REST API gives
+func (c *Client) fromRepositoryNode(node starredRepositoryNode) StarredRepo {
+	repo := StarredRepo{
+		Name:        node.Name,
+		FullName:    node.NameWithOwner,
+		Description: node.Description,
+		URL:         c.client.BaseURL.String() + "repos/" + node.NameWithOwner,
+		HTMLURL:     node.URL,
+		Stars:       node.StargazerCount,
+		Forks:       node.ForkCount,
+		Owner:       node.Owner.Login,
+		Topics:      make([]string, 0, len(node.RepositoryTopics.Nodes)),
+		Archived:    node.IsArchived,
+		Fork:        node.IsFork,
+		IsTemplate:  node.IsTemplate,
+		// REST counts open pull requests as issues
+		OpenIssues: node.Issues.TotalCount + node.PullRequests.TotalCount,
+		Homepage:   node.HomepageURL,
+		Visibility: strings.ToLower(node.Visibility),
+	}
+
+	if node.PrimaryLanguage != nil {
+		repo.Language = node.PrimaryLanguage.Name
+	}
+	for _, topic := range node.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, topic.Topic.Name)
+	}
+	if node.LicenseInfo != nil {
+		repo.License = licenseID(node.LicenseInfo.SPDXID, node.LicenseInfo.Name)
+	}
+	if node.DefaultBranchRef != nil {
+		repo.DefaultBranch = node.DefaultBranchRef.Name
+	}
+	if node.UpdatedAt != nil {
+		repo.UpdatedAt = node.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	if node.PushedAt != nil {
+		repo.PushedAt = node.PushedAt.UTC().Format(time.RFC3339)
+	}
+
+	return repo
+}
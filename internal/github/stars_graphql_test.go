@@ -0,0 +1,148 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetStarredRepos_GraphQL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode GraphQL request: %v", err)
+		}
+
+		switch {
+		case req.Variables["login"] == "ghost":
+			fmt.Fprint(w, `{"data":{"user":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a User"}]}`)
+		case !strings.Contains(req.Query, "viewer"):
+			t.Errorf("query for the authenticated user does not read the viewer: %s", req.Query)
+		case req.Variables["cursor"] == nil:
+			fmt.Fprint(w, `{"data":{"user":{"starredRepositories":{
+				"pageInfo":{"hasNextPage":true,"endCursor":"page-2"},
+				"edges":[{"starredAt":"2024-03-15T10:30:00+01:00","node":{
+					"name":"mcp-go","nameWithOwner":"mark3labs/mcp-go","description":"MCP in Go",
+					"url":"https://github.com/mark3labs/mcp-go","homepageUrl":"https://mcp-go.dev",
+					"owner":{"login":"mark3labs"},"primaryLanguage":{"name":"Go"},
+					"stargazerCount":42,"forkCount":10,
+					"updatedAt":"2024-01-02T03:04:05Z","pushedAt":"2024-01-01T00:00:00Z",
+					"repositoryTopics":{"nodes":[{"topic":{"name":"mcp"}},{"topic":{"name":"golang"}}]},
+					"licenseInfo":{"spdxId":"MIT","name":"MIT License"},
+					"defaultBranchRef":{"name":"main"},
+					"isArchived":false,"isFork":false,"isTemplate":true,"visibility":"PUBLIC",
+					"issues":{"totalCount":3},"pullRequests":{"totalCount":2}}}]}}}}`)
+		case req.Variables["cursor"] == "page-2":
+			fmt.Fprint(w, `{"data":{"user":{"starredRepositories":{
+				"pageInfo":{"hasNextPage":false},
+				"edges":[{"starredAt":"2024-01-01T00:00:00Z","node":{
+					"name":"empty","nameWithOwner":"octocat/empty","description":null,
+					"url":"https://github.com/octocat/empty","homepageUrl":null,
+					"owner":{"login":"octocat"},"primaryLanguage":null,
+					"stargazerCount":0,"forkCount":0,"updatedAt":"2024-01-01T00:00:00Z","pushedAt":null,
+					"repositoryTopics":{"nodes":[]},
+					"licenseInfo":{"spdxId":"NOASSERTION","name":"Other"},
+					"defaultBranchRef":null,"isArchived":true,"isFork":true,"isTemplate":false,"visibility":"PRIVATE",
+					"issues":{"totalCount":0},"pullRequests":{"totalCount":0}}}]}}}}`)
+		default:
+			t.Errorf("unexpected GraphQL request: %+v", req)
+		}
+	})
+	client := newTestClient(t, mux)
+	client.graphQLStars = true
+
+	repos, err := client.GetStarredRepos(context.Background())
+	if err != nil {
+		t.Fatalf("GetStarredRepos() error = %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("GetStarredRepos() returned %d repos, want 2", len(repos))
+	}
+
+	want := StarredRepo{
+		Name:          "mcp-go",
+		FullName:      "mark3labs/mcp-go",
+		Description:   "MCP in Go",
+		URL:           client.client.BaseURL.String() + "repos/mark3labs/mcp-go",
+		HTMLURL:       "https://github.com/mark3labs/mcp-go",
+		Language:      "Go",
+		Stars:         42,
+		Forks:         10,
+		UpdatedAt:     "2024-01-02T03:04:05Z",
+		Owner:         "mark3labs",
+		Topics:        []string{"mcp", "golang"},
+		License:       "MIT",
+		DefaultBranch: "main",
+		IsTemplate:    true,
+		OpenIssues:    5,
+		PushedAt:      "2024-01-01T00:00:00Z",
+		Homepage:      "https://mcp-go.dev",
+		Visibility:    "public",
+		StarredAt:     "2024-03-15T09:30:00Z",
+	}
+	if !reflect.DeepEqual(repos[0], want) {
+		t.Errorf("GetStarredRepos()[0] = %+v, want %+v", repos[0], want)
+	}
+
+	second := repos[1]
+	if second.License != "Other" || second.Language != "" || second.DefaultBranch != "" || second.PushedAt != "" {
+		t.Errorf("GetStarredRepos()[1] = %+v, want null fields left empty", second)
+	}
+	if second.Topics == nil || !second.Archived || !second.Fork || second.Visibility != "private" {
+		t.Errorf("GetStarredRepos()[1] = %+v, want empty topics, archived private fork", second)
+	}
+
+	if _, err := client.GetStarredReposForUser(context.Background(), "ghost"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetStarredReposForUser() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetStarredRepos_GraphQLCached(t *testing.T) {
+	queries := map[string]int{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode GraphQL request: %v", err)
+		}
+		login, _ := req.Variables["login"].(string)
+		queries[login]++
+		fmt.Fprint(w, `{"data":{"user":{"starredRepositories":{"pageInfo":{"hasNextPage":false},
+			"edges":[{"starredAt":"2024-01-01T00:00:00Z","node":{"name":"cli","nameWithOwner":"urfave/cli",
+				"owner":{"login":"urfave"},"repositoryTopics":{"nodes":[]},"visibility":"PUBLIC"}}]}}}}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewClient(context.Background(), "token", WithGraphQLStars(),
+		WithEnterpriseURLs(srv.URL, ""), WithCache(t.TempDir(), time.Hour), WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetStarredRepos(ctx); err != nil {
+			t.Fatalf("GetStarredRepos() error = %v", err)
+		}
+		repos, err := client.GetStarredReposForUser(ctx, "octocat")
+		if err != nil {
+			t.Fatalf("GetStarredReposForUser() error = %v", err)
+		}
+		if len(repos) != 1 || repos[0].FullName != "urfave/cli" {
+			t.Fatalf("GetStarredReposForUser() = %+v, want urfave/cli", repos)
+		}
+	}
+
+	if queries[""] != 1 || queries["octocat"] != 1 {
+		t.Errorf("GraphQL queries = %v, want one per star list while cached", queries)
+	}
+}
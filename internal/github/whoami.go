@@ -0,0 +1,123 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// ErrBadCredentials is returned when GitHub rejects the client's token as
+// invalid, expired or revoked
+var ErrBadCredentials = errors.New("bad credentials")
+
+// impliedScopes lists the OAuth scopes granted by a broader scope
+var impliedScopes = map[string][]string{
+	"repo":      {"public_repo", "repo:status", "repo_deployment", "repo:invite", "security_events"},
+	"user":      {"read:user", "user:email", "user:follow"},
+	"admin:org": {"write:org", "read:org"},
+	"write:org": {"read:org"},
+}
+
+// Identity is the account a Client is authenticated as
+type Identity struct {
+	// Login of the authenticated user; empty for a GitHub App installation
+	Login string
+
+	// InstallationID is set when authenticated as a GitHub App installation
+	InstallationID int64
+
+	// Scopes granted to a classic personal access token or OAuth token. Nil
+	// when GitHub does not report scopes, as for fine-grained tokens.
+	Scopes []string
+
+	// TokenExpiry is when the token expires; zero when it does not expire or
+	// GitHub does not say
+	TokenExpiry time.Time
+}
+
+// HasScope reports whether the token was granted scope, directly or through
+// a broader scope that implies it
+func (i *Identity) HasScope(scope string) bool {
+	for _, granted := range i.Scopes {
+		if granted == scope {
+			return true
+		}
+		for _, implied := range impliedScopes[granted] {
+			if implied == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Whoami returns the account the client is authenticated as, along with the
+// scopes and expiry GitHub reports for its token
+func (c *Client) Whoami(ctx context.Context) (*Identity, error) {
+	if c.installationID != 0 {
+		return c.whoamiInstallation(ctx)
+	}
+
+	// A cached response would report the scopes and expiry the token had
+	// when it was stored, and accept a token revoked since
+	ctx = withoutCache(ctx)
+
+	var user *github.User
+	var resp *github.Response
+	err := c.withRetry(ctx, func() error {
+		var err error
+		user, resp, err = c.client.Users.Get(ctx, "")
+		return err
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			return nil, fmt.Errorf("failed to fetch authenticated user: %w", ErrBadCredentials)
+		}
+		return nil, fmt.Errorf("failed to fetch authenticated user: %w", err)
+	}
+
+	identity := &Identity{
+		Login:       user.GetLogin(),
+		Scopes:      parseScopes(resp.Header),
+		TokenExpiry: resp.TokenExpiration.Time,
+	}
+	return identity, nil
+}
+
+// whoamiInstallation checks that a GitHub App installation can authenticate.
+// Installation tokens cannot read /user, so it lists a single repository of
+// the installation instead.
+func (c *Client) whoamiInstallation(ctx context.Context) (*Identity, error) {
+	err := c.withRetry(ctx, func() error {
+		_, _, err := c.client.Apps.ListRepos(ctx, &github.ListOptions{PerPage: 1})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate as installation %d: %w", c.installationID, err)
+	}
+	return &Identity{InstallationID: c.installationID}, nil
+}
+
+// parseScopes reads the X-OAuth-Scopes header, returning nil when GitHub
+// did not send it
+func parseScopes(header http.Header) []string {
+	values, ok := header[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !ok {
+		return nil
+	}
+
+	scopes := []string{}
+	for _, value := range values {
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,148 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWhoami(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     map[string]string
+		status     int
+		wantScopes []string
+		wantExpiry time.Time
+		wantErr    error
+	}{
+		{
+			name:       "classic token",
+			header:     map[string]string{"X-OAuth-Scopes": "public_repo, read:user"},
+			status:     http.StatusOK,
+			wantScopes: []string{"public_repo", "read:user"},
+		},
+		{
+			name:       "classic token without scopes",
+			header:     map[string]string{"X-OAuth-Scopes": ""},
+			status:     http.StatusOK,
+			wantScopes: []string{},
+		},
+		{
+			name:       "fine-grained token",
+			header:     map[string]string{"GitHub-Authentication-Token-Expiration": "2030-01-02 03:04:05 UTC"},
+			status:     http.StatusOK,
+			wantExpiry: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			name:    "bad credentials",
+			status:  http.StatusUnauthorized,
+			wantErr: ErrBadCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+				for key, value := range tt.header {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusOK {
+					w.Write([]byte(`{"login": "octocat"}`))
+				} else {
+					w.Write([]byte(`{"message": "Bad credentials"}`))
+				}
+			})
+			client := newTestClient(t, mux)
+
+			identity, err := client.Whoami(context.Background())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Whoami() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Whoami() error = %v", err)
+			}
+			if identity.Login != "octocat" {
+				t.Errorf("Login = %q, want octocat", identity.Login)
+			}
+			if !reflect.DeepEqual(identity.Scopes, tt.wantScopes) {
+				t.Errorf("Scopes = %#v, want %#v", identity.Scopes, tt.wantScopes)
+			}
+			if !identity.TokenExpiry.Equal(tt.wantExpiry) {
+				t.Errorf("TokenExpiry = %v, want %v", identity.TokenExpiry, tt.wantExpiry)
+			}
+		})
+	}
+}
+
+func TestWhoami_BypassesCache(t *testing.T) {
+	scopes := []string{"repo", "public_repo"}
+	hits := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"user"`)
+		w.Header().Set("X-OAuth-Scopes", scopes[hits])
+		hits++
+		fmt.Fprint(w, `{"login":"octocat"}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewClient(context.Background(), "token",
+		WithEnterpriseURLs(srv.URL, ""), WithCache(t.TempDir(), time.Hour), WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for _, want := range scopes {
+		identity, err := client.Whoami(context.Background())
+		if err != nil {
+			t.Fatalf("Whoami() error = %v", err)
+		}
+		if !reflect.DeepEqual(identity.Scopes, []string{want}) {
+			t.Errorf("Scopes = %v, want [%s] from a live response", identity.Scopes, want)
+		}
+	}
+}
+
+func TestWhoami_Installation(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/installation/repositories", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total_count": 0, "repositories": []}`))
+	})
+	client := newTestClient(t, mux)
+	client.installationID = 42
+
+	identity, err := client.Whoami(context.Background())
+	if err != nil {
+		t.Fatalf("Whoami() error = %v", err)
+	}
+	if identity.InstallationID != 42 || identity.Login != "" {
+		t.Errorf("identity = %+v, want installation 42 without a login", identity)
+	}
+}
+
+func TestIdentityHasScope(t *testing.T) {
+	identity := &Identity{Scopes: []string{"repo", "read:org"}}
+
+	for scope, want := range map[string]bool{
+		"repo":        true,
+		"public_repo": true,
+		"read:org":    true,
+		"read:user":   false,
+		"write:org":   false,
+	} {
+		if got := identity.HasScope(scope); got != want {
+			t.Errorf("HasScope(%q) = %v, want %v", scope, got, want)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+// BM25 ranking parameters
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Field weights: a name match counts three times as much as a description match
+const (
+	nameWeight        = 3
+	topicWeight       = 2
+	ownerWeight       = 1
+	descriptionWeight = 1
+	readmeWeight      = 1
+)
+
+const (
+	// formatVersion is bumped whenever the on-disk layout changes
+	formatVersion = 1
+
+	// maxReadmeBytes caps the README text indexed per repository
+	maxReadmeBytes = 32 * 1024
+
+	// readmeRefreshInterval is how long an indexed README is kept before it is fetched again
+	readmeRefreshInterval = 7 * 24 * time.Hour
+
+	// maxReadmeFetchesPerUpdate bounds the work done by a single Update so
+	// searches stay responsive while README coverage fills in incrementally
+	maxReadmeFetchesPerUpdate = 50
+)
+
+// ReadmeFunc fetches the README text of a repository
+type ReadmeFunc func(ctx context.Context, repo github.StarredRepo) (string, error)
+
+// Hit is a ranked search result
+type Hit struct {
+	FullName string
+	Score    float64
+}
+
+// document is the indexed form of one starred repository
+type document struct {
+	Fingerprint     string         `json:"fingerprint"`
+	Terms           map[string]int `json:"terms"`
+	ReadmeTerms     map[string]int `json:"readme_terms,omitempty"`
+	ReadmeFetchedAt time.Time      `json:"readme_fetched_at,omitempty"`
+}
+
+// snapshot is the persisted form of the index
+type snapshot struct {
+	Version   int                  `json:"version"`
+	Documents map[string]*document `json:"documents"`
+}
+
+// Index is a BM25 inverted index over starred repositories. Documents are
+// persisted to disk and updated incrementally from the star list.
+type Index struct {
+	// updateMu serializes updates; mu guards the index contents
+	updateMu sync.Mutex
+	mu       sync.RWMutex
+
+	path      string
+	readme    ReadmeFunc
+	docs      map[string]*document
+	postings  map[string]map[string]int
+	lengths   map[string]int
+	avgLength float64
+
+	now func() time.Time
+}
+
+// Open loads the index stored at path. A missing, corrupt or outdated file
+// yields an empty index that is rebuilt on the next update. An empty path
+// keeps the index in memory only. When readme is non-nil, README text is
+// indexed alongside repository metadata.
+func Open(path string, readme ReadmeFunc) (*Index, error) {
+	idx := &Index{
+		path:   path,
+		readme: readme,
+		docs:   make(map[string]*document),
+		now:    time.Now,
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, fmt.Errorf("failed to read search index %s: %w", path, err)
+		default:
+			var snap snapshot
+			if err := json.Unmarshal(data, &snap); err != nil || snap.Version != formatVersion {
+				log.Printf("Discarding unreadable search index %s; it will be rebuilt", path)
+			} else if snap.Documents != nil {
+				idx.docs = snap.Documents
+			}
+		}
+	}
+
+	idx.rebuild()
+	return idx, nil
+}
+
+// Len returns the number of indexed repositories
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.docs)
+}
+
+// Update brings the index in line with repos: new and changed repositories
+// are (re)indexed and unstarred ones removed. READMEs, when enabled, are
+// fetched at most a bounded number per call.
+func (i *Index) Update(ctx context.Context, repos []github.StarredRepo) error {
+	i.updateMu.Lock()
+	defer i.updateMu.Unlock()
+
+	now := i.now()
+	changed := false
+
+	// Work out what needs reindexing without blocking searches
+	i.mu.RLock()
+	updated := make(map[string]*document, len(repos))
+	var needReadme []github.StarredRepo
+	for _, repo := range repos {
+		existing := i.docs[repo.FullName]
+		doc := &document{}
+		if existing != nil {
+			*doc = *existing
+		}
+
+		if fp := fingerprint(repo); existing == nil || existing.Fingerprint != fp {
+			doc.Fingerprint = fp
+			doc.Terms = metadataTerms(repo)
+			changed = true
+		}
+
+		if i.readme != nil && now.Sub(doc.ReadmeFetchedAt) > readmeRefreshInterval {
+			needReadme = append(needReadme, repo)
+		}
+
+		updated[repo.FullName] = doc
+	}
+	if len(updated) != len(i.docs) {
+		changed = true
+	}
+	i.mu.RUnlock()
+
+	// Fetch READMEs outside the lock since each one is a network call
+	var fetchErr error
+	if len(needReadme) > maxReadmeFetchesPerUpdate {
+		needReadme = needReadme[:maxReadmeFetchesPerUpdate]
+	}
+	for _, repo := range needReadme {
+		text, err := i.readme(ctx, repo)
+		if err != nil {
+			if ctx.Err() != nil {
+				fetchErr = ctx.Err()
+				break
+			}
+			if !errors.Is(err, github.ErrNotFound) {
+				log.Printf("Skipping README of %s in search index: %v", repo.FullName, err)
+				continue
+			}
+			// No README: remember that so it is not fetched again until the refresh interval
+			text = ""
+		}
+
+		if len(text) > maxReadmeBytes {
+			text = text[:maxReadmeBytes]
+		}
+
+		doc := updated[repo.FullName]
+		doc.ReadmeTerms = make(map[string]int)
+		addTerms(doc.ReadmeTerms, text, readmeWeight)
+		doc.ReadmeFetchedAt = now
+		changed = true
+	}
+
+	if !changed {
+		return fetchErr
+	}
+
+	i.mu.Lock()
+	i.docs = updated
+	i.rebuild()
+	i.mu.Unlock()
+
+	if err := i.save(); err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// Search ranks indexed repositories against query with BM25 and returns at
+// most limit hits, best first. A limit of zero returns every match.
+func (i *Index) Search(query string, limit int) []Hit {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	scores := make(map[string]float64)
+	total := float64(len(i.docs))
+
+	for _, term := range tokenize(query) {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		postings := i.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		n := float64(len(postings))
+		idf := math.Log(1 + (total-n+0.5)/(n+0.5))
+		for name, count := range postings {
+			tf := float64(count)
+			norm := 1 - b + b*float64(i.lengths[name])/i.avgLength
+			scores[name] += idf * tf * (k1 + 1) / (tf + k1*norm)
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for name, score := range scores {
+		hits = append(hits, Hit{FullName: name, Score: score})
+	}
+	sort.Slice(hits, func(a, c int) bool {
+		if hits[a].Score != hits[c].Score {
+			return hits[a].Score > hits[c].Score
+		}
+		return hits[a].FullName < hits[c].FullName
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// rebuild recomputes postings and document lengths. Callers must hold mu
+// for writing, except during Open.
+func (i *Index) rebuild() {
+	i.postings = make(map[string]map[string]int)
+	i.lengths = make(map[string]int, len(i.docs))
+
+	totalLength := 0
+	for name, doc := range i.docs {
+		length := 0
+		for _, terms := range []map[string]int{doc.Terms, doc.ReadmeTerms} {
+			for term, count := range terms {
+				if i.postings[term] == nil {
+					i.postings[term] = make(map[string]int)
+				}
+				i.postings[term][name] += count
+				length += count
+			}
+		}
+		i.lengths[name] = length
+		totalLength += length
+	}
+
+	i.avgLength = 1
+	if len(i.docs) > 0 && totalLength > 0 {
+		i.avgLength = float64(totalLength) / float64(len(i.docs))
+	}
+}
+
+// save writes the index to disk atomically
+func (i *Index) save() error {
+	if i.path == "" {
+		return nil
+	}
+
+	i.mu.RLock()
+	data, err := json.Marshal(snapshot{Version: formatVersion, Documents: i.docs})
+	i.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+
+	dir := filepath.Dir(i.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create search index directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(i.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), i.path); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+
+	return nil
+}
+
+// fingerprint identifies the indexed metadata of a repository so unchanged
+// repositories are not re-tokenized
+func fingerprint(repo github.StarredRepo) string {
+	return strings.Join([]string{
+		repo.FullName,
+		repo.Description,
+		strings.Join(repo.Topics, ","),
+	}, "\x00")
+}
+
+// metadataTerms returns the weighted term counts for a repository's metadata
+func metadataTerms(repo github.StarredRepo) map[string]int {
+	terms := make(map[string]int)
+	addTerms(terms, repo.Name, nameWeight)
+	addTerms(terms, repo.Owner, ownerWeight)
+	addTerms(terms, repo.Description, descriptionWeight)
+	for _, topic := range repo.Topics {
+		addTerms(terms, topic, topicWeight)
+	}
+	return terms
+}
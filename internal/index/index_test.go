@@ -0,0 +1,141 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+func testRepos() []github.StarredRepo {
+	return []github.StarredRepo{
+		{Name: "axum", FullName: "tokio-rs/axum", Owner: "tokio-rs", Description: "Ergonomic and modular web framework built with Tokio", Topics: []string{"rust", "http"}},
+		{Name: "gin", FullName: "gin-gonic/gin", Owner: "gin-gonic", Description: "Gin is a HTTP web framework written in Go", Topics: []string{"go", "framework"}},
+		{Name: "ripgrep", FullName: "BurntSushi/ripgrep", Owner: "BurntSushi", Description: "Recursively search directories for a regex pattern", Topics: []string{"rust", "cli"}},
+	}
+}
+
+func hitNames(hits []Hit) []string {
+	names := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		names = append(names, hit.FullName)
+	}
+	return names
+}
+
+func TestSearch_Ranking(t *testing.T) {
+	idx, err := Open("", nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := idx.Update(context.Background(), testRepos()); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	hits := idx.Search("rust web framework", 0)
+	names := hitNames(hits)
+	if len(names) != 3 {
+		t.Fatalf("Search() = %v, want 3 hits", names)
+	}
+	if names[0] != "tokio-rs/axum" {
+		t.Errorf("top hit = %v, want tokio-rs/axum (matches all terms)", names[0])
+	}
+	for i := 1; i < len(hits); i++ {
+		if hits[i].Score > hits[i-1].Score {
+			t.Errorf("hits not sorted by score: %v", hits)
+		}
+	}
+
+	if hits := idx.Search("kubernetes", 0); len(hits) != 0 {
+		t.Errorf("Search(kubernetes) = %v, want no hits", hitNames(hits))
+	}
+
+	if hits := idx.Search("rust", 1); len(hits) != 1 {
+		t.Errorf("Search(rust, 1) returned %d hits, want 1", len(hits))
+	}
+}
+
+func TestUpdate_Incremental(t *testing.T) {
+	idx, err := Open("", nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	repos := testRepos()
+	if err := idx.Update(context.Background(), repos); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// Unstar ripgrep and change gin's description
+	repos = repos[:2]
+	repos[1].Description = "High performance router"
+	if err := idx.Update(context.Background(), repos); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if idx.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", idx.Len())
+	}
+	if hits := idx.Search("regex", 0); len(hits) != 0 {
+		t.Errorf("Search(regex) = %v, want unstarred repo removed", hitNames(hits))
+	}
+	if hits := idx.Search("router", 0); len(hits) != 1 || hits[0].FullName != "gin-gonic/gin" {
+		t.Errorf("Search(router) = %v, want gin-gonic/gin", hitNames(hits))
+	}
+}
+
+func TestUpdate_Readmes(t *testing.T) {
+	fetches := 0
+	readme := func(ctx context.Context, repo github.StarredRepo) (string, error) {
+		fetches++
+		if repo.Name == "ripgrep" {
+			return "", fmt.Errorf("README %w", github.ErrNotFound)
+		}
+		return "Supports websockets and middleware for " + repo.Name, nil
+	}
+
+	idx, err := Open("", readme)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := idx.Update(context.Background(), testRepos()); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	hits := idx.Search("websockets", 0)
+	if len(hits) != 2 {
+		t.Errorf("Search(websockets) = %v, want 2 hits from README text", hitNames(hits))
+	}
+
+	// READMEs are not fetched again within the refresh interval, including missing ones
+	if err := idx.Update(context.Background(), testRepos()); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if fetches != 3 {
+		t.Errorf("README fetches = %d, want 3", fetches)
+	}
+}
+
+func TestOpen_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	idx, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := idx.Update(context.Background(), testRepos()); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	reopened, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if reopened.Len() != 3 {
+		t.Errorf("Len() after reopen = %d, want 3", reopened.Len())
+	}
+	if hits := reopened.Search("regex", 0); len(hits) != 1 {
+		t.Errorf("Search(regex) after reopen = %v, want 1 hit", hitNames(hits))
+	}
+}
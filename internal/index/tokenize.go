@@ -0,0 +1,39 @@
+package index
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English words that carry no search signal
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "with": true, "your": true, "you": true,
+}
+
+// tokenize splits text into lowercase terms. Identifiers such as
+// "mcp-go" or "go_github" are split on punctuation, and stop words and
+// single characters are dropped.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len(field) < 2 || stopWords[field] {
+			continue
+		}
+		terms = append(terms, field)
+	}
+	return terms
+}
+
+// addTerms tokenizes text and adds each term to counts with the given weight
+func addTerms(counts map[string]int, text string, weight int) {
+	for _, term := range tokenize(text) {
+		counts[term] += weight
+	}
+}
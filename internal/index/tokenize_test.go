@@ -0,0 +1,44 @@
+package index
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "splits identifiers on punctuation",
+			input:    "mark3labs/mcp-go",
+			expected: []string{"mark3labs", "mcp", "go"},
+		},
+		{
+			name:     "lowercases and drops stop words",
+			input:    "The Go SDK for MCP",
+			expected: []string{"go", "sdk", "mcp"},
+		},
+		{
+			name:     "drops single characters",
+			input:    "a b c rust",
+			expected: []string{"rust"},
+		},
+		{
+			name:     "empty input",
+			input:    "",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tokenize(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("tokenize(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package mcpauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// jwksRefreshInterval is how long fetched signing keys are used before
+	// they are fetched again
+	jwksRefreshInterval = time.Hour
+
+	// jwksMinRefreshInterval limits how often an unknown key ID triggers a
+	// refetch, so forged tokens cannot flood the issuer
+	jwksMinRefreshInterval = time.Minute
+
+	// maxMetadataBytes caps the size of fetched metadata and key sets
+	maxMetadataBytes = 1 << 20
+)
+
+// KeySet supplies the public keys tokens may be signed with
+type KeySet interface {
+	// Keys returns the candidate keys for the key ID of a token, which may be empty
+	Keys(ctx context.Context, keyID string) ([]crypto.PublicKey, error)
+}
+
+// StaticKeys is a fixed set of public keys
+type StaticKeys []crypto.PublicKey
+
+// Keys implements KeySet; static keys have no IDs, so all of them are candidates
+func (k StaticKeys) Keys(ctx context.Context, keyID string) ([]crypto.PublicKey, error) {
+	return k, nil
+}
+
+// ParsePublicKeys parses the PEM-encoded RSA and ECDSA public keys in data
+func ParsePublicKeys(data []byte) (StaticKeys, error) {
+	var keys StaticKeys
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse public key: %w", err)
+			}
+			keys = append(keys, key)
+		case "RSA PUBLIC KEY":
+			key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse public key: %w", err)
+			}
+			keys = append(keys, key)
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate: %w", err)
+			}
+			keys = append(keys, cert.PublicKey)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys found")
+	}
+	return keys, nil
+}
+
+// IssuerKeys fetches the signing keys of an OAuth authorization server from
+// the jwks_uri in its metadata and caches them
+type IssuerKeys struct {
+	issuer string
+	client *http.Client
+	now    func() time.Time
+
+	// fetching makes concurrent requests share one fetch of the keys
+	fetching singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+
+	// failedAt and fetchErr record the last failed fetch, after which the
+	// keys are not fetched again for jwksMinRefreshInterval
+	failedAt time.Time
+	fetchErr error
+}
+
+// NewIssuerKeys returns the key set of the authorization server issuer
+func NewIssuerKeys(issuer string, client *http.Client) *IssuerKeys {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &IssuerKeys{
+		issuer: issuer,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Keys implements KeySet. Keys are refetched once they are stale, or when
+// keyID is unknown, which happens after the issuer rotates its keys. The
+// fetch runs without holding the lock, and concurrent requests share it.
+// After a failed fetch the previous keys, or the error when there are none,
+// are served until jwksMinRefreshInterval has passed, so an issuer outage
+// does not stall every request on a fetch.
+func (k *IssuerKeys) Keys(ctx context.Context, keyID string) ([]crypto.PublicKey, error) {
+	if k.stale(keyID) {
+		_, err, _ := k.fetching.Do("", func() (any, error) {
+			// Another request may have refreshed the keys meanwhile
+			if !k.stale(keyID) {
+				return nil, nil
+			}
+
+			// The fetch serves every waiting request, so the first one
+			// going away must not cancel it; the client's timeout bounds it
+			keys, err := k.fetch(context.WithoutCancel(ctx))
+
+			k.mu.Lock()
+			defer k.mu.Unlock()
+			if err != nil {
+				k.failedAt = k.now()
+				k.fetchErr = err
+				return nil, nil
+			}
+			k.keys = keys
+			k.fetchedAt = k.now()
+			k.fetchErr = nil
+			return nil, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	// Keep serving the previous keys through an outage
+	if k.keys == nil && k.fetchErr != nil {
+		return nil, k.fetchErr
+	}
+
+	if keyID != "" {
+		if key, ok := k.keys[keyID]; ok {
+			return []crypto.PublicKey{key}, nil
+		}
+		return nil, nil
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// stale reports whether the keys need to be fetched to look up keyID
+func (k *IssuerKeys) stale(keyID string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.fetchErr != nil && k.now().Sub(k.failedAt) < jwksMinRefreshInterval {
+		return false
+	}
+
+	age := k.now().Sub(k.fetchedAt)
+	_, known := k.keys[keyID]
+	return k.keys == nil || age > jwksRefreshInterval || (keyID != "" && !known && age > jwksMinRefreshInterval)
+}
+
+// fetch discovers the issuer's jwks_uri and reads its key set
+func (k *IssuerKeys) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	jwksURI, err := k.discoverJWKSURI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := k.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip key types this server cannot verify
+			continue
+		}
+
+		kid := jwk.KeyID
+		if kid == "" {
+			kid = fmt.Sprintf("#%d", i)
+		}
+		keys[kid] = key
+	}
+	return keys, nil
+}
+
+// discoverJWKSURI reads the jwks_uri from the issuer's OAuth authorization
+// server metadata (RFC 8414), falling back to OpenID Connect discovery
+func (k *IssuerKeys) discoverJWKSURI(ctx context.Context) (string, error) {
+	var lastErr error
+	for _, metadataURL := range metadataURLs(k.issuer) {
+		var metadata struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := k.getJSON(ctx, metadataURL, &metadata); err != nil {
+			lastErr = err
+			continue
+		}
+		if metadata.JWKSURI != "" {
+			return metadata.JWKSURI, nil
+		}
+		lastErr = fmt.Errorf("no jwks_uri in %s", metadataURL)
+	}
+	return "", fmt.Errorf("failed to discover signing keys of %s: %w", k.issuer, lastErr)
+}
+
+// metadataURLs lists the URLs the metadata of issuer may be published at, in
+// the order they are tried. RFC 8414 inserts the well-known segment between
+// the host and the path of the issuer (https://host/.well-known/oauth-authorization-server/tenant),
+// while OpenID Connect appends it to the issuer (https://host/tenant/.well-known/openid-configuration).
+// Some servers publish the RFC 8414 document at the appended location as
+// well, which is tried last.
+func metadataURLs(issuer string) []string {
+	const (
+		oauthPath = "/.well-known/oauth-authorization-server"
+		oidcPath  = "/.well-known/openid-configuration"
+	)
+
+	trimmed := strings.TrimSuffix(issuer, "/")
+	u, err := url.Parse(trimmed)
+	if err != nil || u.Path == "" {
+		return []string{trimmed + oauthPath, trimmed + oidcPath}
+	}
+
+	inserted := u.Scheme + "://" + u.Host + oauthPath + u.EscapedPath()
+	return []string{inserted, trimmed + oidcPath, trimmed + oauthPath}
+}
+
+// getJSON fetches url and decodes its JSON body into v
+func (k *IssuerKeys) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataBytes))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// jsonWebKey is an RSA or EC public key in JWK form (RFC 7517)
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+// publicKey converts the JWK to a Go public key
+func (j jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch j.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if j.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Curve)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(j.X)
+		y, errY := base64.RawURLEncoding.DecodeString(j.Y)
+		if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return nil, fmt.Errorf("invalid key parameter")
+		}
+		point := append(append([]byte{4}, x...), y...)
+		return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", j.KeyType)
+	}
+}
+
+// decodeBigInt decodes a base64url-encoded unsigned big-endian integer
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,318 @@
+package mcpauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParsePublicKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	ecDER, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+	}
+
+	data := append(
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecDER})...,
+	)
+
+	keys, err := ParsePublicKeys(data)
+	if err != nil {
+		t.Fatalf("ParsePublicKeys() error = %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("ParsePublicKeys() returned %d keys, want 2", len(keys))
+	}
+	if !rsaKey.PublicKey.Equal(keys[0]) || !ecKey.PublicKey.Equal(keys[1]) {
+		t.Error("ParsePublicKeys() returned different keys")
+	}
+
+	if _, err := ParsePublicKeys([]byte("no keys here")); err == nil {
+		t.Error("ParsePublicKeys() error = nil, want error for data without keys")
+	}
+}
+
+func TestIssuerKeys(t *testing.T) {
+	first, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	rotated, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	current := map[string]*ecdsa.PrivateKey{"first": first}
+	fetches := 0
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"issuer":%q,"jwks_uri":%q}`, srv.URL, srv.URL+"/jwks")
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		fmt.Fprint(w, `{"keys":[`)
+		i := 0
+		for kid, key := range current {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"kty":"EC","crv":"P-256","kid":%q,"use":"sig","x":%q,"y":%q}`, kid,
+				base64.RawURLEncoding.EncodeToString(key.PublicKey.X.FillBytes(make([]byte, 32))),
+				base64.RawURLEncoding.EncodeToString(key.PublicKey.Y.FillBytes(make([]byte, 32))))
+			i++
+		}
+		fmt.Fprint(w, `]}`)
+	})
+
+	now := time.Unix(1700000000, 0)
+	keys := NewIssuerKeys(srv.URL, srv.Client())
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	found, err := keys.Keys(ctx, "first")
+	if err != nil {
+		t.Fatalf("Keys() error = %v", err)
+	}
+	if len(found) != 1 || !first.PublicKey.Equal(found[0]) {
+		t.Fatalf("Keys(first) = %v, want the first key", found)
+	}
+
+	// An unknown key ID right after a fetch does not refetch
+	current["rotated"] = rotated
+	if found, _ := keys.Keys(ctx, "rotated"); len(found) != 0 || fetches != 1 {
+		t.Errorf("Keys(rotated) = %v after %d fetches, want no keys from the cached set", found, fetches)
+	}
+
+	// Once the minimum refresh interval has passed, it does
+	now = now.Add(2 * jwksMinRefreshInterval)
+	found, err = keys.Keys(ctx, "rotated")
+	if err != nil {
+		t.Fatalf("Keys() error = %v", err)
+	}
+	if len(found) != 1 || !rotated.PublicKey.Equal(found[0]) || fetches != 2 {
+		t.Errorf("Keys(rotated) = %v after %d fetches, want the rotated key after 2", found, fetches)
+	}
+
+	// Tokens signed with the issuer's keys verify
+	token := signToken(t, "ES256", rotated, "rotated", validClaims(time.Now()))
+	verifier := NewVerifier("https://auth.example.com", testAudience, keys)
+	if _, err := verifier.Verify(ctx, token); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+// TestIssuerKeys_ConcurrentFetch tests that requests arriving while the keys
+// are fetched share that fetch, and that the fetch does not block requests
+// that need no keys from it
+func TestIssuerKeys_ConcurrentFetch(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"issuer":%q,"jwks_uri":%q}`, srv.URL, srv.URL+"/jwks")
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		fmt.Fprintf(w, `{"keys":[{"kty":"EC","crv":"P-256","kid":"k1","x":%q,"y":%q}]}`,
+			base64.RawURLEncoding.EncodeToString(key.PublicKey.X.FillBytes(make([]byte, 32))),
+			base64.RawURLEncoding.EncodeToString(key.PublicKey.Y.FillBytes(make([]byte, 32))))
+	})
+
+	keys := NewIssuerKeys(srv.URL, srv.Client())
+
+	var wg sync.WaitGroup
+	results := make(chan int, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found, err := keys.Keys(context.Background(), "k1")
+			if err != nil {
+				t.Errorf("Keys() error = %v", err)
+			}
+			results <- len(found)
+		}()
+	}
+
+	// The lock is free while the fetch is in flight
+	for fetches.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	done := make(chan struct{})
+	go func() {
+		keys.stale("k1")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the keys are locked while they are fetched")
+	}
+
+	close(release)
+	wg.Wait()
+	close(results)
+	for n := range results {
+		if n != 1 {
+			t.Errorf("Keys(k1) returned %d keys, want 1", n)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetched the keys %d times, want 1", n)
+	}
+}
+
+// TestIssuerKeys_Outage tests that failed fetches are not retried on every
+// request, while the keys fetched before keep being served
+func TestIssuerKeys_Outage(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	down := true
+	attempts := 0
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"issuer":%q,"jwks_uri":%q}`, srv.URL, srv.URL+"/jwks")
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"keys":[{"kty":"EC","crv":"P-256","kid":"k1","x":%q,"y":%q}]}`,
+			base64.RawURLEncoding.EncodeToString(key.PublicKey.X.FillBytes(make([]byte, 32))),
+			base64.RawURLEncoding.EncodeToString(key.PublicKey.Y.FillBytes(make([]byte, 32))))
+	})
+
+	now := time.Unix(1700000000, 0)
+	keys := NewIssuerKeys(srv.URL, srv.Client())
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Without keys the failure is reported, but not retried right away
+	for i := 0; i < 3; i++ {
+		if _, err := keys.Keys(ctx, "k1"); err == nil {
+			t.Fatal("Keys() expected error while the issuer is down, got nil")
+		}
+	}
+	if attempts != 1 {
+		t.Errorf("fetched %d times during the outage, want 1", attempts)
+	}
+
+	// Once the interval has passed the keys are fetched again
+	down = false
+	now = now.Add(2 * jwksMinRefreshInterval)
+	if found, err := keys.Keys(ctx, "k1"); err != nil || len(found) != 1 {
+		t.Fatalf("Keys(k1) = %v, %v; want the key", found, err)
+	}
+
+	// Stale keys keep being served while a refetch fails
+	down = true
+	attempts = 0
+	now = now.Add(2 * jwksRefreshInterval)
+	for i := 0; i < 3; i++ {
+		if found, err := keys.Keys(ctx, "k1"); err != nil || len(found) != 1 {
+			t.Fatalf("Keys(k1) = %v, %v; want the previous key", found, err)
+		}
+	}
+	if attempts != 1 {
+		t.Errorf("fetched %d times during the outage, want 1", attempts)
+	}
+}
+
+func TestMetadataURLs(t *testing.T) {
+	tests := []struct {
+		issuer   string
+		expected []string
+	}{
+		{
+			issuer: "https://auth.example.com/",
+			expected: []string{
+				"https://auth.example.com/.well-known/oauth-authorization-server",
+				"https://auth.example.com/.well-known/openid-configuration",
+			},
+		},
+		{
+			issuer: "https://auth.example.com/tenant",
+			expected: []string{
+				"https://auth.example.com/.well-known/oauth-authorization-server/tenant",
+				"https://auth.example.com/tenant/.well-known/openid-configuration",
+				"https://auth.example.com/tenant/.well-known/oauth-authorization-server",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		if urls := metadataURLs(tt.issuer); !reflect.DeepEqual(urls, tt.expected) {
+			t.Errorf("metadataURLs(%q) = %v, want %v", tt.issuer, urls, tt.expected)
+		}
+	}
+}
+
+// TestIssuerKeys_IssuerWithPath tests discovery of an issuer with a path,
+// whose RFC 8414 metadata lives under the host's well-known directory
+func TestIssuerKeys_IssuerWithPath(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/oauth-authorization-server/tenant", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"issuer":%q,"jwks_uri":%q}`, srv.URL+"/tenant", srv.URL+"/tenant/jwks")
+	})
+	mux.HandleFunc("/tenant/jwks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"keys":[{"kty":"EC","crv":"P-256","kid":"k1","x":%q,"y":%q}]}`,
+			base64.RawURLEncoding.EncodeToString(key.PublicKey.X.FillBytes(make([]byte, 32))),
+			base64.RawURLEncoding.EncodeToString(key.PublicKey.Y.FillBytes(make([]byte, 32))))
+	})
+
+	keys := NewIssuerKeys(srv.URL+"/tenant", srv.Client())
+	if found, err := keys.Keys(context.Background(), "k1"); err != nil || len(found) != 1 {
+		t.Errorf("Keys(k1) = %v, %v; want the tenant's key", found, err)
+	}
+}
@@ -0,0 +1,87 @@
+package mcpauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// claimsKey is the context key of the verified token claims
+type claimsKey struct{}
+
+// WithClaims returns a copy of ctx carrying claims
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims of the token that authorized the request
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// Middleware rejects requests without a valid bearer token, as the MCP
+// authorization spec requires: the 401 response points clients at
+// metadataURL, the server's protected resource metadata. Tokens without
+// ScopeRead, which every request needs, get a 403 asking for it. The claims
+// of accepted tokens are added to the request context.
+func Middleware(verifier *Verifier, metadataURL string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				challenge(w, metadataURL, "", "")
+				return
+			}
+
+			claims, err := verifier.Verify(r.Context(), token)
+			if err != nil {
+				if !errors.Is(err, ErrInvalidToken) {
+					log.Printf("Failed to verify access token: %v", err)
+				}
+				challenge(w, metadataURL, "invalid_token", "the access token is invalid or expired")
+				return
+			}
+			if !claims.HasScope(ScopeRead) {
+				insufficientScope(w, metadataURL, ScopeRead)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// bearerToken extracts the token of an "Authorization: Bearer" header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// challenge writes a 401 response with the WWW-Authenticate header of RFC 6750
+// and RFC 9728
+func challenge(w http.ResponseWriter, metadataURL, code, description string) {
+	value := fmt.Sprintf("Bearer resource_metadata=%q", metadataURL)
+	if code != "" {
+		value += fmt.Sprintf(", error=%q, error_description=%q", code, description)
+	}
+
+	w.Header().Set("WWW-Authenticate", value)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
+
+// insufficientScope writes a 403 response asking for a token with scope, as
+// RFC 6750 describes
+func insufficientScope(w http.ResponseWriter, metadataURL, scope string) {
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+		"Bearer resource_metadata=%q, error=\"insufficient_scope\", scope=%q, error_description=%q",
+		metadataURL, scope, "the access token lacks the "+scope+" scope"))
+	http.Error(w, "insufficient scope", http.StatusForbidden)
+}
@@ -0,0 +1,97 @@
+package mcpauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	verifier := NewVerifier("https://auth.example.com", testAudience, StaticKeys{&key.PublicKey})
+	metadataURL := "https://mcp.example.com/.well-known/oauth-protected-resource/mcp"
+
+	var claims *Claims
+	handler := Middleware(verifier, metadataURL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ = ClaimsFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	expired := validClaims(time.Now())
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	writeOnly := validClaims(time.Now())
+	writeOnly["scope"] = ScopeWrite
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantError     string
+	}{
+		{
+			name:          "valid token",
+			authorization: "Bearer " + signToken(t, "ES256", key, "", validClaims(time.Now())),
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:       "no token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "other scheme",
+			authorization: "Basic dXNlcjpwYXNz",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "expired token",
+			authorization: "Bearer " + signToken(t, "ES256", key, "", expired),
+			wantStatus:    http.StatusUnauthorized,
+			wantError:     `error="invalid_token"`,
+		},
+		{
+			name:          "token without the read scope",
+			authorization: "Bearer " + signToken(t, "ES256", key, "", writeOnly),
+			wantStatus:    http.StatusForbidden,
+			wantError:     `error="insufficient_scope", scope="starred:read"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims = nil
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK {
+				if claims == nil || claims.Subject != "user-1" {
+					t.Errorf("claims in context = %+v, want subject user-1", claims)
+				}
+				return
+			}
+
+			challenge := rec.Header().Get("WWW-Authenticate")
+			if !strings.HasPrefix(challenge, "Bearer ") || !strings.Contains(challenge, `resource_metadata="`+metadataURL+`"`) {
+				t.Errorf("WWW-Authenticate = %q, want a Bearer challenge with the resource metadata", challenge)
+			}
+			if !strings.Contains(challenge, tt.wantError) {
+				t.Errorf("WWW-Authenticate = %q, want %s", challenge, tt.wantError)
+			}
+		})
+	}
+}
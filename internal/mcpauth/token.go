@@ -0,0 +1,245 @@
+package mcpauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scopes a client token can carry
+const (
+	// ScopeRead allows reading resources and calling read-only tools
+	ScopeRead = "starred:read"
+
+	// ScopeWrite allows calling tools that change GitHub state, such as star_repo
+	ScopeWrite = "starred:write"
+)
+
+// clockSkew is the leeway allowed when checking token lifetimes
+const clockSkew = time.Minute
+
+// ErrInvalidToken is returned for tokens that are malformed, expired, signed
+// with an unknown key or issued for another resource
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the verified claims of a client access token
+type Claims struct {
+	Subject  string
+	ClientID string
+	Scopes   []string
+	Expiry   time.Time
+}
+
+// HasScope reports whether the token grants scope
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+// Verifier validates JWT access tokens issued for this server
+type Verifier struct {
+	issuer   string
+	audience string
+	keys     KeySet
+	now      func() time.Time
+}
+
+// NewVerifier returns a verifier accepting tokens signed by one of keys for
+// audience, the server's resource URL. An empty issuer accepts any issuer,
+// which is only sensible with static keys.
+func NewVerifier(issuer, audience string, keys KeySet) *Verifier {
+	return &Verifier{
+		issuer:   issuer,
+		audience: audience,
+		keys:     keys,
+		now:      time.Now,
+	}
+}
+
+// jwtHeader is the JOSE header of a token
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// jwtClaims is the payload of a token
+type jwtClaims struct {
+	Issuer          string      `json:"iss"`
+	Subject         string      `json:"sub"`
+	Audience        stringList  `json:"aud"`
+	Expiry          numericDate `json:"exp"`
+	NotBefore       numericDate `json:"nbf"`
+	Scope           string      `json:"scope"`
+	Scp             scopeList   `json:"scp"`
+	ClientID        string      `json:"client_id"`
+	AuthorizedParty string      `json:"azp"`
+}
+
+// Verify checks the signature, issuer, audience and lifetime of token and
+// returns its claims
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a JWT", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: bad header: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad signature encoding", ErrInvalidToken)
+	}
+
+	keys, err := v.keys.Keys(ctx, header.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token signing keys: %w", err)
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	if !slices.ContainsFunc(keys, func(key crypto.PublicKey) bool {
+		return verifySignature(header.Algorithm, key, signed, signature)
+	}) {
+		return nil, fmt.Errorf("%w: signature does not match any key", ErrInvalidToken)
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: bad claims: %v", ErrInvalidToken, err)
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+
+	clientID := claims.ClientID
+	if clientID == "" {
+		clientID = claims.AuthorizedParty
+	}
+	scopes := strings.Fields(claims.Scope)
+	if len(scopes) == 0 {
+		scopes = claims.Scp
+	}
+
+	return &Claims{
+		Subject:  claims.Subject,
+		ClientID: clientID,
+		Scopes:   scopes,
+		Expiry:   time.Unix(int64(claims.Expiry), 0),
+	}, nil
+}
+
+// validate checks the registered claims of a token
+func (v *Verifier) validate(claims jwtClaims) error {
+	now := v.now()
+
+	if claims.Expiry == 0 {
+		return fmt.Errorf("%w: no expiry", ErrInvalidToken)
+	}
+	if now.Add(-clockSkew).Unix() >= int64(claims.Expiry) {
+		return fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if claims.NotBefore != 0 && now.Add(clockSkew).Unix() < int64(claims.NotBefore) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("%w: issued by %q", ErrInvalidToken, claims.Issuer)
+	}
+	if !slices.Contains(claims.Audience, v.audience) {
+		return fmt.Errorf("%w: not issued for %s", ErrInvalidToken, v.audience)
+	}
+	return nil
+}
+
+// verifySignature checks an RS256 or ES256 signature; other algorithms,
+// including "none" and HMAC, are rejected
+func verifySignature(algorithm string, key crypto.PublicKey, signed, signature []byte) bool {
+	digest := sha256.Sum256(signed)
+
+	switch algorithm {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature) == nil
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(ecKey, digest[:], r, s)
+	default:
+		return false
+	}
+}
+
+// decodeSegment decodes a base64url JSON segment of a token
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// stringList is a claim holding a single string or an array of strings, such
+// as aud. A single string is one value, even if it contains spaces.
+type stringList []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = stringList{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// scopeList is the scp claim: an array of scopes or, as some authorization
+// servers send it, a space-separated string of them
+type scopeList []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *scopeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = strings.Fields(single)
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// numericDate is a claim holding seconds since the epoch, possibly fractional
+type numericDate int64
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *numericDate) UnmarshalJSON(data []byte) error {
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid numeric date %s", data)
+	}
+	*d = numericDate(seconds)
+	return nil
+}
@@ -0,0 +1,200 @@
+package mcpauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testAudience = "https://mcp.example.com/mcp"
+
+// signToken returns a JWT with claims signed by key using alg
+func signToken(t *testing.T, alg string, key crypto.Signer, kid string, claims map[string]any) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatalf("SignPKCS1v15() error = %v", err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatalf("ecdsa.Sign() error = %v", err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// validClaims returns the claims of a token the test verifiers accept
+func validClaims(now time.Time) map[string]any {
+	return map[string]any{
+		"iss":       "https://auth.example.com",
+		"sub":       "user-1",
+		"aud":       testAudience,
+		"exp":       now.Add(time.Hour).Unix(),
+		"client_id": "client-1",
+		"scope":     "starred:read starred:write",
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	with := func(key string, value any) map[string]any {
+		claims := validClaims(now)
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	scpClaims := with("scope", nil)
+	scpClaims["aud"] = []string{"https://other.example.com", testAudience}
+	scpClaims["scp"] = []string{"starred:read", "starred:write"}
+	scpStringClaims := with("scope", nil)
+	scpStringClaims["scp"] = "starred:read starred:write"
+
+	tests := []struct {
+		name       string
+		token      string
+		wantScopes []string
+		wantErr    bool
+	}{
+		{
+			name:       "RS256",
+			token:      signToken(t, "RS256", rsaKey, "", validClaims(now)),
+			wantScopes: []string{"starred:read", "starred:write"},
+		},
+		{
+			name:       "ES256",
+			token:      signToken(t, "ES256", ecKey, "", validClaims(now)),
+			wantScopes: []string{"starred:read", "starred:write"},
+		},
+		{
+			name:       "audience list and scp claim",
+			token:      signToken(t, "ES256", ecKey, "", scpClaims),
+			wantScopes: []string{"starred:read", "starred:write"},
+		},
+		{
+			name:       "scp claim as a string",
+			token:      signToken(t, "ES256", ecKey, "", scpStringClaims),
+			wantScopes: []string{"starred:read", "starred:write"},
+		},
+		{
+			name:    "audience string is not split at spaces",
+			token:   signToken(t, "ES256", ecKey, "", with("aud", "https://other.example.com "+testAudience)),
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			token:   signToken(t, "ES256", otherKey, "", validClaims(now)),
+			wantErr: true,
+		},
+		{
+			name:    "algorithm mismatch",
+			token:   signToken(t, "RS256", ecKey, "", validClaims(now)),
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			token:   signToken(t, "ES256", ecKey, "", with("exp", now.Add(-time.Hour).Unix())),
+			wantErr: true,
+		},
+		{
+			name:    "no expiry",
+			token:   signToken(t, "ES256", ecKey, "", with("exp", nil)),
+			wantErr: true,
+		},
+		{
+			name:    "not valid yet",
+			token:   signToken(t, "ES256", ecKey, "", with("nbf", now.Add(time.Hour).Unix())),
+			wantErr: true,
+		},
+		{
+			name:    "other issuer",
+			token:   signToken(t, "ES256", ecKey, "", with("iss", "https://evil.example.com")),
+			wantErr: true,
+		},
+		{
+			name:    "other audience",
+			token:   signToken(t, "ES256", ecKey, "", with("aud", "https://other.example.com")),
+			wantErr: true,
+		},
+		{
+			name:    "not a JWT",
+			token:   "gho_abc",
+			wantErr: true,
+		},
+	}
+
+	verifier := NewVerifier("https://auth.example.com", testAudience, StaticKeys{&rsaKey.PublicKey, &ecKey.PublicKey})
+	verifier.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("Verify() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+
+			if claims.Subject != "user-1" || claims.ClientID != "client-1" {
+				t.Errorf("Verify() = %+v, want subject user-1 and client client-1", claims)
+			}
+			if !reflect.DeepEqual(claims.Scopes, tt.wantScopes) {
+				t.Errorf("Scopes = %v, want %v", claims.Scopes, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestClaims_HasScope(t *testing.T) {
+	claims := &Claims{Scopes: []string{ScopeRead}}
+	if !claims.HasScope(ScopeRead) {
+		t.Errorf("HasScope(%q) = false, want true", ScopeRead)
+	}
+	if claims.HasScope(ScopeWrite) {
+		t.Errorf("HasScope(%q) = true, want false", ScopeWrite)
+	}
+}
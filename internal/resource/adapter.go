@@ -1,22 +1,100 @@
 package resource
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/timduly4/mcp-server/internal/github"
+	"github.com/timduly4/mcp-server/internal/index"
 )
 
+// MaxReadmeBytes caps the README text returned for a repository
+const MaxReadmeBytes = 64 * 1024
+
+// ErrNotStarred is returned when a requested repository is not starred by the authenticated user
+var ErrNotStarred = errors.New("not found in starred repos")
+
+// DefaultURIBase is the prefix of resource URIs for github.com
+const DefaultURIBase = "github://"
+
 // Adapter converts GitHub data to MCP resource format
 type Adapter struct {
 	githubClient *github.Client
+	searchIndex  *index.Index
+
+	// uriBase prefixes every resource URI; empty means DefaultURIBase
+	uriBase string
 }
 
-// NewAdapter creates a new resource adapter
-func NewAdapter(githubClient *github.Client) *Adapter {
+// NewAdapter creates a new resource adapter. searchIndex may be nil, which
+// disables ranked full-text search.
+func NewAdapter(githubClient *github.Client, searchIndex *index.Index) *Adapter {
 	return &Adapter{
 		githubClient: githubClient,
+		searchIndex:  searchIndex,
+		uriBase:      URIBase(githubClient.Host()),
+	}
+}
+
+// IsInstallation reports whether the adapter reads GitHub as a GitHub App
+// installation, which has no star list of its own
+func (a *Adapter) IsInstallation() bool {
+	return a.githubClient.IsInstallation()
+}
+
+// identityKey is the context key of the identity serving a request
+type identityKey struct{}
+
+// identity is the GitHub client and search index of one GitHub user
+type identity struct {
+	client      *github.Client
+	searchIndex *index.Index
+}
+
+// WithIdentity returns a copy of ctx in which the adapter reads GitHub
+// through client and ranks with searchIndex instead of its own, so one
+// adapter can serve several GitHub users. searchIndex may be nil.
+func WithIdentity(ctx context.Context, client *github.Client, searchIndex *index.Index) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity{client: client, searchIndex: searchIndex})
+}
+
+// clientFor returns the GitHub client serving ctx
+func (a *Adapter) clientFor(ctx context.Context) *github.Client {
+	if id, ok := ctx.Value(identityKey{}).(identity); ok {
+		return id.client
+	}
+	return a.githubClient
+}
+
+// indexFor returns the search index serving ctx, or nil when ranked search is disabled
+func (a *Adapter) indexFor(ctx context.Context) *index.Index {
+	if id, ok := ctx.Value(identityKey{}).(identity); ok {
+		return id.searchIndex
 	}
+	return a.searchIndex
+}
+
+// URIBase returns the prefix of resource URIs for a GitHub host: github://
+// for github.com (an empty host) and github://{host}/ for a GitHub Enterprise
+// Server, so resources of different hosts never share URIs
+func URIBase(host string) string {
+	if host == "" {
+		return DefaultURIBase
+	}
+	return DefaultURIBase + host + "/"
+}
+
+// uri returns the resource URI for path, such as starred/{owner}/{repo}
+func (a *Adapter) uri(path string) string {
+	if a.uriBase == "" {
+		return DefaultURIBase + path
+	}
+	return a.uriBase + path
 }
 
 // MCPResource represents a resource in MCP format
@@ -29,8 +107,8 @@ type MCPResource struct {
 }
 
 // ListStarredResources returns starred repositories as MCP resources
-func (a *Adapter) ListStarredResources() ([]MCPResource, error) {
-	repos, err := a.githubClient.GetStarredRepos()
+func (a *Adapter) ListStarredResources(ctx context.Context) ([]MCPResource, error) {
+	repos, err := a.clientFor(ctx).GetStarredRepos(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get starred repos: %w", err)
 	}
@@ -40,30 +118,61 @@ func (a *Adapter) ListStarredResources() ([]MCPResource, error) {
 		resource := a.repoToMCPResource(repo)
 		resources = append(resources, resource)
 	}
+	a.addListNames(ctx, resources)
 
 	return resources, nil
 }
 
-// GetStarredResource returns a specific starred repository as an MCP resource
-func (a *Adapter) GetStarredResource(fullName string) (*MCPResource, error) {
-	repos, err := a.githubClient.GetStarredRepos()
+// GetStarredResource returns a specific starred repository as an MCP resource.
+// The repository is fetched directly and its star status checked, rather than
+// scanning the full star list.
+func (a *Adapter) GetStarredResource(ctx context.Context, fullName string) (*MCPResource, error) {
+	owner, name, err := splitFullName(fullName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get starred repos: %w", err)
+		return nil, err
 	}
 
-	for _, repo := range repos {
-		if repo.FullName == fullName {
-			resource := a.repoToMCPResource(repo)
-			return &resource, nil
+	if err := a.requireStarred(ctx, owner, name); err != nil {
+		return nil, err
+	}
+
+	repo, err := a.clientFor(ctx).GetRepo(ctx, owner, name)
+	if err != nil {
+		if errors.Is(err, github.ErrNotFound) {
+			return nil, fmt.Errorf("repository %s %w", fullName, ErrNotStarred)
 		}
+		return nil, fmt.Errorf("failed to get starred repo %s: %w", fullName, err)
 	}
 
-	return nil, fmt.Errorf("repository %s not found in starred repos", fullName)
+	resource := a.repoToMCPResource(*repo)
+	a.addListNames(ctx, []MCPResource{resource})
+	return &resource, nil
+}
+
+// GetStarredReadme returns the README of a starred repository as markdown,
+// truncated to MaxReadmeBytes. Repositories without a README or that are not
+// starred return ErrNotStarred or an error wrapping github.ErrNotFound.
+func (a *Adapter) GetStarredReadme(ctx context.Context, fullName string) (string, error) {
+	owner, name, err := splitFullName(fullName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := a.requireStarred(ctx, owner, name); err != nil {
+		return "", err
+	}
+
+	readme, err := a.clientFor(ctx).GetReadme(ctx, owner, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get README of %s: %w", fullName, err)
+	}
+
+	return truncateReadme(readme, MaxReadmeBytes), nil
 }
 
 // ListStarredResourcesForUser returns starred repositories for a specific user as MCP resources
-func (a *Adapter) ListStarredResourcesForUser(username string) ([]MCPResource, error) {
-	repos, err := a.githubClient.GetStarredReposForUser(username)
+func (a *Adapter) ListStarredResourcesForUser(ctx context.Context, username string) ([]MCPResource, error) {
+	repos, err := a.clientFor(ctx).GetStarredReposForUser(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get starred repos for user %s: %w", username, err)
 	}
@@ -77,22 +186,78 @@ func (a *Adapter) ListStarredResourcesForUser(username string) ([]MCPResource, e
 	return resources, nil
 }
 
-// repoToMCPResource converts a GitHub starred repo to MCP resource format
-func (a *Adapter) repoToMCPResource(repo github.StarredRepo) MCPResource {
-	uri := fmt.Sprintf("github://starred/%s", repo.FullName)
+// GetRateLimitResource returns the remaining GitHub API quota as an MCP resource
+func (a *Adapter) GetRateLimitResource(ctx context.Context) (*MCPResource, error) {
+	limits, err := a.clientFor(ctx).GetRateLimits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate limits: %w", err)
+	}
 
+	resource := a.rateLimitsToMCPResource(limits)
+	return &resource, nil
+}
+
+// GetWhoamiResource returns the account the GitHub token belongs to, with
+// its scopes and expiry, as an MCP resource
+func (a *Adapter) GetWhoamiResource(ctx context.Context) (*MCPResource, error) {
+	identity, err := a.clientFor(ctx).Whoami(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authenticated identity: %w", err)
+	}
+
+	resource := a.identityToMCPResource(identity)
+	return &resource, nil
+}
+
+// identityToMCPResource converts a GitHub identity to MCP resource format.
+// scopes is null when GitHub does not report the token's scopes.
+func (a *Adapter) identityToMCPResource(identity *github.Identity) MCPResource {
 	contents := map[string]interface{}{
-		"name":        repo.Name,
-		"full_name":   repo.FullName,
-		"owner":       repo.Owner,
-		"description": repo.Description,
-		"url":         repo.URL,
-		"html_url":    repo.HTMLURL,
-		"language":    repo.Language,
-		"stars":       repo.Stars,
-		"forks":       repo.Forks,
-		"updated_at":  repo.UpdatedAt,
+		"scopes": identity.Scopes,
+	}
+	if identity.Login != "" {
+		contents["login"] = identity.Login
+	}
+	if identity.InstallationID != 0 {
+		contents["installation_id"] = identity.InstallationID
+	}
+	if !identity.TokenExpiry.IsZero() {
+		contents["token_expires_at"] = identity.TokenExpiry.UTC().Format(time.RFC3339)
+	}
+
+	return MCPResource{
+		URI:         a.uri("whoami"),
+		Name:        "GitHub Identity",
+		Description: "The GitHub account the server reads stars as, with its token's scopes and expiry",
+		MimeType:    "application/json",
+		Contents:    contents,
+	}
+}
+
+// rateLimitsToMCPResource converts GitHub rate limits to MCP resource format
+func (a *Adapter) rateLimitsToMCPResource(limits []github.RateLimit) MCPResource {
+	contents := make(map[string]interface{}, len(limits))
+	for _, limit := range limits {
+		contents[limit.Resource] = map[string]interface{}{
+			"limit":     limit.Limit,
+			"remaining": limit.Remaining,
+			"reset":     limit.Reset.UTC().Format(time.RFC3339),
+		}
+	}
+
+	return MCPResource{
+		URI:         a.uri("ratelimit"),
+		Name:        "GitHub API Rate Limit",
+		Description: "Remaining GitHub API quota per rate limit category",
+		MimeType:    "application/json",
+		Contents:    contents,
 	}
+}
+
+// repoToMCPResource converts a GitHub starred repo to MCP resource format
+func (a *Adapter) repoToMCPResource(repo github.StarredRepo) MCPResource {
+	uri := a.uri("starred/" + repo.FullName)
+	contents := repoContents(repo)
 
 	description := repo.Description
 	if description == "" {
@@ -110,21 +275,10 @@ func (a *Adapter) repoToMCPResource(repo github.StarredRepo) MCPResource {
 
 // repoToMCPResourceForUser converts a GitHub starred repo to MCP resource format for a specific user
 func (a *Adapter) repoToMCPResourceForUser(repo github.StarredRepo, username string) MCPResource {
-	uri := fmt.Sprintf("github://starred/users/%s/%s", username, repo.FullName)
+	uri := a.uri(fmt.Sprintf("starred/users/%s/%s", username, repo.FullName))
 
-	contents := map[string]interface{}{
-		"name":        repo.Name,
-		"full_name":   repo.FullName,
-		"owner":       repo.Owner,
-		"description": repo.Description,
-		"url":         repo.URL,
-		"html_url":    repo.HTMLURL,
-		"language":    repo.Language,
-		"stars":       repo.Stars,
-		"forks":       repo.Forks,
-		"updated_at":  repo.UpdatedAt,
-		"starred_by":  username,
-	}
+	contents := repoContents(repo)
+	contents["starred_by"] = username
 
 	description := repo.Description
 	if description == "" {
@@ -140,6 +294,59 @@ func (a *Adapter) repoToMCPResourceForUser(repo github.StarredRepo, username str
 	}
 }
 
+// repoContents returns the metadata of a repository as MCP resource contents
+func repoContents(repo github.StarredRepo) map[string]interface{} {
+	contents := map[string]interface{}{
+		"name":           repo.Name,
+		"full_name":      repo.FullName,
+		"owner":          repo.Owner,
+		"description":    repo.Description,
+		"url":            repo.URL,
+		"html_url":       repo.HTMLURL,
+		"homepage":       repo.Homepage,
+		"language":       repo.Language,
+		"license":        repo.License,
+		"topics":         repo.Topics,
+		"stars":          repo.Stars,
+		"forks":          repo.Forks,
+		"open_issues":    repo.OpenIssues,
+		"default_branch": repo.DefaultBranch,
+		"visibility":     repo.Visibility,
+		"archived":       repo.Archived,
+		"fork":           repo.Fork,
+		"is_template":    repo.IsTemplate,
+		"updated_at":     repo.UpdatedAt,
+		"pushed_at":      repo.PushedAt,
+	}
+	if repo.StarredAt != "" {
+		contents["starred_at"] = repo.StarredAt
+	}
+	return contents
+}
+
+// truncateReadme cuts text to at most limit bytes on a UTF-8 boundary and
+// notes the truncation so the reader knows the README continues
+func truncateReadme(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + fmt.Sprintf("\n\n[README truncated at %d of %d bytes]\n", cut, len(text))
+}
+
+// splitFullName splits an owner/repo name into its parts
+func splitFullName(fullName string) (owner, repo string, err error) {
+	owner, repo, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("invalid repository name %q: expected owner/repo", fullName)
+	}
+	return owner, repo, nil
+}
+
 // ToJSON converts resources to JSON format
 func (a *Adapter) ToJSON(resources []MCPResource) ([]byte, error) {
 	data, err := json.MarshalIndent(resources, "", "  ")
@@ -1,9 +1,21 @@
 package resource
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/timduly4/mcp-server/internal/github"
+	"github.com/timduly4/mcp-server/internal/index"
 )
 
 func TestRepoToMCPResource(t *testing.T) {
@@ -20,6 +32,9 @@ func TestRepoToMCPResource(t *testing.T) {
 		Forks:       10,
 		UpdatedAt:   "2024-01-01",
 		Owner:       "owner",
+		StarredAt:   "2024-02-01T12:00:00Z",
+		License:     "MIT",
+		Archived:    true,
 	}
 
 	resource := adapter.repoToMCPResource(repo)
@@ -48,6 +63,52 @@ func TestRepoToMCPResource(t *testing.T) {
 	if resource.Contents["stars"] != repo.Stars {
 		t.Errorf("Contents[stars] = %v, want %v", resource.Contents["stars"], repo.Stars)
 	}
+
+	if resource.Contents["license"] != "MIT" || resource.Contents["archived"] != true {
+		t.Errorf("Contents license/archived = %v/%v, want MIT/true", resource.Contents["license"], resource.Contents["archived"])
+	}
+
+	if resource.Contents["starred_at"] != repo.StarredAt {
+		t.Errorf("Contents[starred_at] = %v, want %v", resource.Contents["starred_at"], repo.StarredAt)
+	}
+}
+
+func TestURIBase(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{
+			name:     "github.com",
+			host:     "",
+			expected: "github://",
+		},
+		{
+			name:     "enterprise server",
+			host:     "ghe.example.com",
+			expected: "github://ghe.example.com/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := URIBase(tt.host); result != tt.expected {
+				t.Errorf("URIBase(%q) = %q, want %q", tt.host, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepoToMCPResource_EnterpriseHost(t *testing.T) {
+	adapter := &Adapter{uriBase: URIBase("ghe.example.com")}
+
+	resource := adapter.repoToMCPResource(github.StarredRepo{FullName: "owner/test-repo"})
+
+	expectedURI := "github://ghe.example.com/starred/owner/test-repo"
+	if resource.URI != expectedURI {
+		t.Errorf("URI = %v, want %v", resource.URI, expectedURI)
+	}
 }
 
 func TestRepoToMCPResource_EmptyDescription(t *testing.T) {
@@ -166,6 +227,111 @@ func TestRepoToMCPResourceForUser_EmptyDescription(t *testing.T) {
 	}
 }
 
+func TestRateLimitsToMCPResource(t *testing.T) {
+	adapter := &Adapter{}
+
+	reset := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limits := []github.RateLimit{
+		{Resource: "core", Limit: 5000, Remaining: 4200, Reset: reset},
+		{Resource: "search", Limit: 30, Remaining: 30, Reset: reset},
+	}
+
+	resource := adapter.rateLimitsToMCPResource(limits)
+
+	if resource.URI != "github://ratelimit" {
+		t.Errorf("URI = %v, want github://ratelimit", resource.URI)
+	}
+
+	core, ok := resource.Contents["core"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Contents[core] = %v, want map", resource.Contents["core"])
+	}
+	if core["remaining"] != 4200 {
+		t.Errorf("core remaining = %v, want 4200", core["remaining"])
+	}
+	if core["reset"] != "2024-01-01T12:00:00Z" {
+		t.Errorf("core reset = %v, want 2024-01-01T12:00:00Z", core["reset"])
+	}
+	if _, ok := resource.Contents["search"]; !ok {
+		t.Error("Contents missing search rate limit")
+	}
+}
+
+func TestIdentityToMCPResource(t *testing.T) {
+	adapter := &Adapter{}
+
+	resource := adapter.identityToMCPResource(&github.Identity{
+		Login:       "octocat",
+		Scopes:      []string{"public_repo"},
+		TokenExpiry: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	if resource.URI != "github://whoami" {
+		t.Errorf("URI = %v, want github://whoami", resource.URI)
+	}
+	if resource.Contents["login"] != "octocat" {
+		t.Errorf("login = %v, want octocat", resource.Contents["login"])
+	}
+	if resource.Contents["token_expires_at"] != "2030-01-01T00:00:00Z" {
+		t.Errorf("token_expires_at = %v, want 2030-01-01T00:00:00Z", resource.Contents["token_expires_at"])
+	}
+	if _, ok := resource.Contents["installation_id"]; ok {
+		t.Error("Contents has installation_id for a user token")
+	}
+
+	installation := adapter.identityToMCPResource(&github.Identity{InstallationID: 42})
+	if installation.Contents["installation_id"] != int64(42) {
+		t.Errorf("installation_id = %v, want 42", installation.Contents["installation_id"])
+	}
+	if _, ok := installation.Contents["login"]; ok {
+		t.Error("Contents has a login for an installation")
+	}
+}
+
+func TestSplitFullName(t *testing.T) {
+	tests := []struct {
+		name      string
+		fullName  string
+		wantOwner string
+		wantRepo  string
+		wantErr   bool
+	}{
+		{
+			name:      "valid owner and repo",
+			fullName:  "mark3labs/mcp-go",
+			wantOwner: "mark3labs",
+			wantRepo:  "mcp-go",
+		},
+		{
+			name:     "missing repo",
+			fullName: "mark3labs/",
+			wantErr:  true,
+		},
+		{
+			name:     "missing slash",
+			fullName: "mark3labs",
+			wantErr:  true,
+		},
+		{
+			name:     "too many segments",
+			fullName: "mark3labs/mcp-go/extra",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, repo, err := splitFullName(tt.fullName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitFullName(%q) error = %v, wantErr %v", tt.fullName, err, tt.wantErr)
+			}
+			if owner != tt.wantOwner || repo != tt.wantRepo {
+				t.Errorf("splitFullName(%q) = %q, %q, want %q, %q", tt.fullName, owner, repo, tt.wantOwner, tt.wantRepo)
+			}
+		})
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && findSubstring(s, substr))
 }
@@ -178,3 +344,109 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestTruncateReadme(t *testing.T) {
+	short := "# Title"
+	if result := truncateReadme(short, 100); result != short {
+		t.Errorf("truncateReadme() = %q, want unchanged", result)
+	}
+
+	// "é" is two bytes, so a limit of 2 falls inside it
+	result := truncateReadme("aéb", 2)
+	if !strings.HasPrefix(result, "a\n\n[README truncated at 1 of 4 bytes]") {
+		t.Errorf("truncateReadme() = %q, want cut before the multi-byte rune", result)
+	}
+}
+
+func TestWithIdentity(t *testing.T) {
+	shared := &github.Client{}
+	sharedIndex, _ := index.Open("", nil)
+	adapter := &Adapter{githubClient: shared, searchIndex: sharedIndex}
+
+	ctx := context.Background()
+	if adapter.clientFor(ctx) != shared || adapter.indexFor(ctx) != sharedIndex {
+		t.Error("expected the adapter's own client and index without an identity")
+	}
+
+	tenant := &github.Client{}
+	ctx = WithIdentity(ctx, tenant, nil)
+	if adapter.clientFor(ctx) != tenant {
+		t.Error("expected the identity's client")
+	}
+	if adapter.indexFor(ctx) != nil {
+		t.Error("expected the identity's index, not the adapter's")
+	}
+}
+
+// newTestAdapter returns an Adapter whose GitHub client talks to a test
+// server backed by mux. The client uses the GitHub Enterprise Server layout,
+// so mux paths start with /api/v3/.
+func newTestAdapter(t *testing.T, mux *http.ServeMux, opts ...github.Option) *Adapter {
+	t.Helper()
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	opts = append(opts, github.WithEnterpriseURLs(srv.URL, ""), github.WithRetry(0, 0))
+	client, err := github.NewClient(context.Background(), "test-token", opts...)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return NewAdapter(client, nil)
+}
+
+func TestGetStarredResource_NotStarred(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/user/starred/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	adapter := newTestAdapter(t, mux)
+
+	_, err := adapter.GetStarredResource(context.Background(), "owner/repo")
+	if !errors.Is(err, ErrNotStarred) {
+		t.Errorf("GetStarredResource() error = %v, want ErrNotStarred", err)
+	}
+}
+
+func TestGetStarredResource_Installation(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"ghs_test","expires_at":%q}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	})
+	mux.HandleFunc("/api/v3/user/starred/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("installation client checked the star, which GitHub always refuses")
+		w.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc("/api/v3/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"repo","full_name":"owner/repo","owner":{"login":"owner"}}`)
+	})
+	mux.HandleFunc("/api/v3/repos/owner/repo/readme", func(w http.ResponseWriter, r *http.Request) {
+		// "# repo\n" base64-encoded, as returned by the contents API
+		fmt.Fprint(w, `{"type":"file","encoding":"base64","name":"README.md","content":"IyByZXBvCg=="}`)
+	})
+	adapter := newTestAdapter(t, mux, github.WithAppInstallation(7, 42, pemKey))
+	ctx := context.Background()
+
+	resource, err := adapter.GetStarredResource(ctx, "owner/repo")
+	if err != nil {
+		t.Fatalf("GetStarredResource() error = %v", err)
+	}
+	if resource.Name != "owner/repo" {
+		t.Errorf("Name = %q, want owner/repo", resource.Name)
+	}
+
+	readme, err := adapter.GetStarredReadme(ctx, "owner/repo")
+	if err != nil {
+		t.Fatalf("GetStarredReadme() error = %v", err)
+	}
+	if readme != "# repo\n" {
+		t.Errorf("GetStarredReadme() = %q, want %q", readme, "# repo\n")
+	}
+}
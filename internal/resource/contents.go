@@ -0,0 +1,251 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+// MaxFileBytes caps the size of a file returned from a starred repository
+const MaxFileBytes = 256 * 1024
+
+// MaxTreeEntries caps the number of paths listed for a starred repository
+const MaxTreeEntries = 5000
+
+// ErrFileTooLarge is returned for files larger than MaxFileBytes
+var ErrFileTooLarge = errors.New("file too large")
+
+// RepoTree lists the paths of a starred repository at a ref
+type RepoTree struct {
+	Repository string `json:"repository"`
+	Ref        string `json:"ref"`
+	SHA        string `json:"sha"`
+
+	// Truncated is set when the listing does not include every path
+	Truncated bool `json:"truncated"`
+
+	Entries []RepoTreeEntry `json:"entries"`
+}
+
+// RepoTreeEntry is a single path in a RepoTree. Files carry the URI of their
+// blob resource.
+type RepoTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Size int    `json:"size,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// RepoFile is a file of a starred repository
+type RepoFile struct {
+	Path     string
+	MIMEType string
+	Content  []byte
+
+	// Binary is set when Content is not text and must be sent base64-encoded
+	Binary bool
+}
+
+// extensionMIMETypes covers source and config files that the standard
+// library's extension table does not know about
+var extensionMIMETypes = map[string]string{
+	".bzl":      "text/x-python",
+	".c":        "text/x-c",
+	".cc":       "text/x-c++",
+	".cpp":      "text/x-c++",
+	".cs":       "text/x-csharp",
+	".go":       "text/x-go",
+	".h":        "text/x-c",
+	".hpp":      "text/x-c++",
+	".java":     "text/x-java",
+	".js":       "text/javascript",
+	".json":     "application/json",
+	".jsx":      "text/javascript",
+	".kt":       "text/x-kotlin",
+	".lua":      "text/x-lua",
+	".markdown": "text/markdown",
+	".md":       "text/markdown",
+	".mjs":      "text/javascript",
+	".mod":      "text/plain",
+	".php":      "text/x-php",
+	".proto":    "text/x-protobuf",
+	".py":       "text/x-python",
+	".rb":       "text/x-ruby",
+	".rs":       "text/x-rust",
+	".rst":      "text/x-rst",
+	".sh":       "text/x-shellscript",
+	".sql":      "application/sql",
+	".sum":      "text/plain",
+	".swift":    "text/x-swift",
+	".toml":     "application/toml",
+	".ts":       "text/x-typescript",
+	".tsx":      "text/x-typescript",
+	".txt":      "text/plain",
+	".xml":      "application/xml",
+	".yaml":     "application/yaml",
+	".yml":      "application/yaml",
+}
+
+// textMIMETypes are non-text/* types whose content is text
+var textMIMETypes = map[string]bool{
+	"application/javascript": true,
+	"application/json":       true,
+	"application/sql":        true,
+	"application/toml":       true,
+	"application/xml":        true,
+	"application/yaml":       true,
+	"image/svg+xml":          true,
+}
+
+// GetStarredTree lists the files and directories of a starred repository at
+// ref, capped at MaxTreeEntries
+func (a *Adapter) GetStarredTree(ctx context.Context, fullName, ref string) (*RepoTree, error) {
+	owner, name, err := splitFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.requireStarred(ctx, owner, name); err != nil {
+		return nil, err
+	}
+
+	tree, err := a.clientFor(ctx).GetTree(ctx, owner, name, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of %s: %w", fullName, err)
+	}
+
+	return a.toRepoTree(fullName, ref, tree, MaxTreeEntries), nil
+}
+
+// GetStarredFile returns a file of a starred repository at ref along with its
+// MIME type. Files larger than MaxFileBytes return ErrFileTooLarge.
+func (a *Adapter) GetStarredFile(ctx context.Context, fullName, ref, filePath string) (*RepoFile, error) {
+	owner, name, err := splitFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.requireStarred(ctx, owner, name); err != nil {
+		return nil, err
+	}
+
+	file, err := a.clientFor(ctx).GetFileContent(ctx, owner, name, ref, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s of %s: %w", filePath, fullName, err)
+	}
+	if file.Size > MaxFileBytes || len(file.Content) > MaxFileBytes {
+		return nil, fmt.Errorf("%s of %s is %d bytes, limit is %d: %w", filePath, fullName, file.Size, MaxFileBytes, ErrFileTooLarge)
+	}
+
+	mimeType := detectMIMEType(filePath, file.Content)
+	return &RepoFile{
+		Path:     filePath,
+		MIMEType: mimeType,
+		Content:  file.Content,
+		Binary:   !isTextMIMEType(mimeType) || !utf8.Valid(file.Content),
+	}, nil
+}
+
+// requireStarred returns ErrNotStarred unless owner/name is starred by the
+// authenticated user. GitHub App installations have no stars, so for them any
+// repository the installation can read is allowed.
+func (a *Adapter) requireStarred(ctx context.Context, owner, name string) error {
+	client := a.clientFor(ctx)
+	if client.IsInstallation() {
+		return nil
+	}
+
+	starred, err := client.IsStarred(ctx, owner, name)
+	if err != nil {
+		return fmt.Errorf("failed to get starred repo %s/%s: %w", owner, name, err)
+	}
+	if !starred {
+		return fmt.Errorf("repository %s/%s %w", owner, name, ErrNotStarred)
+	}
+	return nil
+}
+
+// toRepoTree converts a GitHub tree, keeping at most limit entries
+func (a *Adapter) toRepoTree(fullName, ref string, tree *github.Tree, limit int) *RepoTree {
+	entries := tree.Entries
+	truncated := tree.Truncated
+	if len(entries) > limit {
+		entries = entries[:limit]
+		truncated = true
+	}
+
+	result := &RepoTree{
+		Repository: fullName,
+		Ref:        ref,
+		SHA:        tree.SHA,
+		Truncated:  truncated,
+		Entries:    make([]RepoTreeEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		treeEntry := RepoTreeEntry{
+			Path: entry.Path,
+			Type: entry.Type,
+			Size: entry.Size,
+		}
+		if entry.Type == "blob" {
+			treeEntry.URI = a.uri(fmt.Sprintf("starred/%s/blob/%s/%s", fullName, escapeURISegment(ref), escapeURIPath(entry.Path)))
+		}
+		result.Entries = append(result.Entries, treeEntry)
+	}
+
+	return result
+}
+
+// escapeURISegment percent-encodes s as a single segment of a resource URI,
+// keeping only unreserved characters, so that a ref such as feature/login
+// stays one segment (feature%2Flogin) and matches the {ref} of the templates
+func escapeURISegment(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// escapeURIPath percent-encodes each segment of a slash-separated file path
+func escapeURIPath(filePath string) string {
+	segments := strings.Split(filePath, "/")
+	for i, segment := range segments {
+		segments[i] = escapeURISegment(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// detectMIMEType picks a MIME type from the file name, falling back to
+// sniffing the content
+func detectMIMEType(filePath string, content []byte) string {
+	ext := strings.ToLower(path.Ext(filePath))
+	if mimeType, ok := extensionMIMETypes[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		mimeType, _, _ = strings.Cut(mimeType, ";")
+		return mimeType
+	}
+
+	mimeType, _, _ := strings.Cut(http.DetectContentType(content), ";")
+	if mimeType == "application/octet-stream" && utf8.Valid(content) && !strings.ContainsRune(string(content), 0) {
+		return "text/plain"
+	}
+	return mimeType
+}
+
+// isTextMIMEType reports whether content of mimeType can be returned as text
+func isTextMIMEType(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") || textMIMETypes[mimeType]
+}
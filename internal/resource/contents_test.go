@@ -0,0 +1,98 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+func TestDetectMIMEType(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		content  []byte
+		expected string
+	}{
+		{name: "go source", path: "cmd/server/main.go", content: []byte("package main\n"), expected: "text/x-go"},
+		{name: "markdown", path: "docs/README.MD", content: []byte("# Title\n"), expected: "text/markdown"},
+		{name: "yaml", path: ".github/workflows/ci.yml", content: []byte("on: push\n"), expected: "application/yaml"},
+		{name: "png", path: "logo.png", content: []byte("\x89PNG\r\n\x1a\n"), expected: "image/png"},
+		{name: "extensionless text", path: "Makefile", content: []byte("all:\n\tgo build\n"), expected: "text/plain"},
+		{name: "extensionless binary", path: "bin/tool", content: []byte{0x7f, 'E', 'L', 'F', 0, 0, 0xff}, expected: "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detectMIMEType(tt.path, tt.content)
+			if result != tt.expected {
+				t.Errorf("detectMIMEType(%q) = %q, want %q", tt.path, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsTextMIMEType(t *testing.T) {
+	tests := map[string]bool{
+		"text/x-go":                true,
+		"application/json":         true,
+		"image/svg+xml":            true,
+		"image/png":                false,
+		"application/octet-stream": false,
+	}
+
+	for mimeType, expected := range tests {
+		if result := isTextMIMEType(mimeType); result != expected {
+			t.Errorf("isTextMIMEType(%q) = %v, want %v", mimeType, result, expected)
+		}
+	}
+}
+
+func TestToRepoTree(t *testing.T) {
+	tree := &github.Tree{
+		SHA: "abc123",
+		Entries: []github.TreeEntry{
+			{Path: "mcp", Type: "tree"},
+			{Path: "mcp/tools.go", Type: "blob", Size: 1024},
+			{Path: "vendor/lib", Type: "commit"},
+		},
+	}
+
+	adapter := &Adapter{}
+	result := adapter.toRepoTree("mark3labs/mcp-go", "main", tree, 2)
+
+	if !result.Truncated {
+		t.Error("toRepoTree() over the limit should be truncated")
+	}
+	if len(result.Entries) != 2 {
+		t.Fatalf("toRepoTree() returned %d entries, want 2", len(result.Entries))
+	}
+	if result.Entries[0].URI != "" {
+		t.Errorf("directory entry URI = %q, want empty", result.Entries[0].URI)
+	}
+	if want := "github://starred/mark3labs/mcp-go/blob/main/mcp/tools.go"; result.Entries[1].URI != want {
+		t.Errorf("file entry URI = %q, want %q", result.Entries[1].URI, want)
+	}
+
+	if result := adapter.toRepoTree("mark3labs/mcp-go", "main", tree, MaxTreeEntries); result.Truncated {
+		t.Error("toRepoTree() under the limit should not be truncated")
+	}
+}
+
+func TestToRepoTree_EscapesURIs(t *testing.T) {
+	tree := &github.Tree{
+		Entries: []github.TreeEntry{
+			{Path: "docs/getting started?.md", Type: "blob"},
+		},
+	}
+
+	adapter := &Adapter{}
+	result := adapter.toRepoTree("mark3labs/mcp-go", "feature/login", tree, MaxTreeEntries)
+
+	want := "github://starred/mark3labs/mcp-go/blob/feature%2Flogin/docs/getting%20started%3F.md"
+	if result.Entries[0].URI != want {
+		t.Errorf("file entry URI = %q, want %q", result.Entries[0].URI, want)
+	}
+	if result.Ref != "feature/login" {
+		t.Errorf("Ref = %q, want the unescaped ref", result.Ref)
+	}
+}
@@ -0,0 +1,159 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+// ErrListNotFound is returned when the authenticated user has no star list with a requested slug
+var ErrListNotFound = errors.New("star list not found")
+
+// StarList summarises one of the authenticated user's star lists
+type StarList struct {
+	URI             string `json:"uri"`
+	Name            string `json:"name"`
+	Slug            string `json:"slug"`
+	Description     string `json:"description,omitempty"`
+	Private         bool   `json:"private"`
+	UpdatedAt       string `json:"updated_at"`
+	RepositoryCount int    `json:"repository_count"`
+}
+
+// StarListResource is a star list along with its repositories
+type StarListResource struct {
+	StarList
+	Repositories []MCPResource `json:"repositories"`
+}
+
+// ListStarLists returns the authenticated user's star lists
+func (a *Adapter) ListStarLists(ctx context.Context) ([]StarList, error) {
+	lists, err := a.clientFor(ctx).GetStarLists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get star lists: %w", err)
+	}
+
+	result := make([]StarList, 0, len(lists))
+	for _, list := range lists {
+		result = append(result, a.toStarList(list))
+	}
+	return result, nil
+}
+
+// GetStarList returns the star list with the given slug and its repositories,
+// in list order
+func (a *Adapter) GetStarList(ctx context.Context, slug string) (*StarListResource, error) {
+	lists, err := a.clientFor(ctx).GetStarLists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get star lists: %w", err)
+	}
+
+	var list *github.StarList
+	for i := range lists {
+		if lists[i].Slug == slug {
+			list = &lists[i]
+			break
+		}
+	}
+	if list == nil {
+		return nil, fmt.Errorf("%w: %s", ErrListNotFound, slug)
+	}
+
+	repos, err := a.clientFor(ctx).GetStarredRepos(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get starred repos: %w", err)
+	}
+	byName := make(map[string]github.StarredRepo, len(repos))
+	for _, repo := range repos {
+		byName[repo.FullName] = repo
+	}
+
+	names := listNamesByRepo(lists)
+	resources := make([]MCPResource, 0, len(list.Repositories))
+	for _, fullName := range list.Repositories {
+		repo, ok := byName[fullName]
+		if !ok {
+			// Lists can still hold repositories that were unstarred since
+			fetched, err := a.fetchRepo(ctx, fullName)
+			if err != nil {
+				log.Printf("Skipping %s in star list %s: %v", fullName, list.Name, err)
+				continue
+			}
+			repo = *fetched
+		}
+
+		resource := a.repoToMCPResource(repo)
+		resource.Contents["lists"] = listsOf(names, fullName)
+		resources = append(resources, resource)
+	}
+
+	return &StarListResource{
+		StarList:     a.toStarList(*list),
+		Repositories: resources,
+	}, nil
+}
+
+// fetchRepo fetches a single repository by full name
+func (a *Adapter) fetchRepo(ctx context.Context, fullName string) (*github.StarredRepo, error) {
+	owner, name, err := splitFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+	return a.clientFor(ctx).GetRepo(ctx, owner, name)
+}
+
+// addListNames records in each resource's contents the names of the star
+// lists containing it. Star lists are a convenience, so failing to read them
+// (for example with a token that cannot use the GraphQL API) is only logged.
+func (a *Adapter) addListNames(ctx context.Context, resources []MCPResource) {
+	if len(resources) == 0 {
+		return
+	}
+
+	lists, err := a.clientFor(ctx).GetStarLists(ctx)
+	if err != nil {
+		log.Printf("Failed to get star lists: %v", err)
+		return
+	}
+
+	names := listNamesByRepo(lists)
+	for _, resource := range resources {
+		resource.Contents["lists"] = listsOf(names, resource.Name)
+	}
+}
+
+// listNamesByRepo maps each repository full name to the names of the lists containing it
+func listNamesByRepo(lists []github.StarList) map[string][]string {
+	names := make(map[string][]string)
+	for _, list := range lists {
+		for _, fullName := range list.Repositories {
+			names[fullName] = append(names[fullName], list.Name)
+		}
+	}
+	return names
+}
+
+// listsOf returns the list names of fullName, never nil so that repositories
+// in no list show an empty list rather than null
+func listsOf(names map[string][]string, fullName string) []string {
+	if lists := names[fullName]; lists != nil {
+		return lists
+	}
+	return []string{}
+}
+
+// toStarList converts a GitHub star list to its MCP summary
+func (a *Adapter) toStarList(list github.StarList) StarList {
+	return StarList{
+		URI:             a.uri("starred/lists/" + list.Slug),
+		Name:            list.Name,
+		Slug:            list.Slug,
+		Description:     list.Description,
+		Private:         list.IsPrivate,
+		UpdatedAt:       list.UpdatedAt,
+		RepositoryCount: len(list.Repositories),
+	}
+}
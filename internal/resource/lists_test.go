@@ -0,0 +1,55 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+func TestListNamesByRepo(t *testing.T) {
+	lists := []github.StarList{
+		{Name: "Go tools", Repositories: []string{"spf13/cobra", "urfave/cli"}},
+		{Name: "CLI", Repositories: []string{"urfave/cli"}},
+		{Name: "Empty"},
+	}
+
+	names := listNamesByRepo(lists)
+
+	tests := map[string][]string{
+		"spf13/cobra":      {"Go tools"},
+		"urfave/cli":       {"Go tools", "CLI"},
+		"mark3labs/mcp-go": {},
+	}
+	for fullName, expected := range tests {
+		if result := listsOf(names, fullName); !reflect.DeepEqual(result, expected) {
+			t.Errorf("listsOf(%q) = %#v, want %#v", fullName, result, expected)
+		}
+	}
+}
+
+func TestToStarList(t *testing.T) {
+	list := github.StarList{
+		Name:         "Go tools",
+		Slug:         "go-tools",
+		Description:  "CLIs",
+		IsPrivate:    true,
+		UpdatedAt:    "2024-06-01T10:00:00Z",
+		Repositories: []string{"spf13/cobra", "urfave/cli"},
+	}
+
+	result := (&Adapter{}).toStarList(list)
+
+	expected := StarList{
+		URI:             "github://starred/lists/go-tools",
+		Name:            "Go tools",
+		Slug:            "go-tools",
+		Description:     "CLIs",
+		Private:         true,
+		UpdatedAt:       "2024-06-01T10:00:00Z",
+		RepositoryCount: 2,
+	}
+	if result != expected {
+		t.Errorf("toStarList() = %+v, want %+v", result, expected)
+	}
+}
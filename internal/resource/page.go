@@ -0,0 +1,122 @@
+package resource
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// MaxPageSize is the largest number of repositories returned in one page
+const MaxPageSize = 100
+
+// ResourcePage is one page of a paginated starred repository listing
+type ResourcePage struct {
+	Repositories []MCPResource `json:"repositories"`
+
+	// NextCursor fetches the following page; it is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// pageCursor is the decoded form of an opaque pagination cursor. The page
+// size is kept in the cursor so later pages line up with the first one.
+type pageCursor struct {
+	Page    int `json:"p"`
+	PerPage int `json:"n"`
+}
+
+// ListStarredResourcesPage returns one page of the authenticated user's
+// starred repositories. An empty cursor starts at the first page with
+// pageSize entries; a cursor continues from a previous page. Repositories not
+// matching filter are dropped from the page, so a filtered page may hold
+// fewer than pageSize entries, or none, and still have a next cursor.
+func (a *Adapter) ListStarredResourcesPage(ctx context.Context, cursor string, pageSize int, filter SearchOptions) (*ResourcePage, error) {
+	page, err := startPage(cursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, nextPage, err := a.clientFor(ctx).GetStarredReposPage(ctx, "", page.Page, page.PerPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get starred repos: %w", err)
+	}
+
+	resources := make([]MCPResource, 0, len(repos))
+	for _, repo := range repos {
+		if filter.matches(repo) {
+			resources = append(resources, a.repoToMCPResource(repo))
+		}
+	}
+	a.addListNames(ctx, resources)
+
+	return &ResourcePage{
+		Repositories: resources,
+		NextCursor:   nextCursor(nextPage, page.PerPage),
+	}, nil
+}
+
+// ListStarredResourcesForUserPage returns one page of a user's starred
+// repositories, filtered like ListStarredResourcesPage
+func (a *Adapter) ListStarredResourcesForUserPage(ctx context.Context, username, cursor string, pageSize int, filter SearchOptions) (*ResourcePage, error) {
+	page, err := startPage(cursor, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, nextPage, err := a.clientFor(ctx).GetStarredReposPage(ctx, username, page.Page, page.PerPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get starred repos for user %s: %w", username, err)
+	}
+
+	resources := make([]MCPResource, 0, len(repos))
+	for _, repo := range repos {
+		if filter.matches(repo) {
+			resources = append(resources, a.repoToMCPResourceForUser(repo, username))
+		}
+	}
+
+	return &ResourcePage{
+		Repositories: resources,
+		NextCursor:   nextCursor(nextPage, page.PerPage),
+	}, nil
+}
+
+// startPage works out which page to fetch from a cursor or, without one, a page size
+func startPage(cursor string, pageSize int) (pageCursor, error) {
+	if cursor != "" {
+		return decodeCursor(cursor)
+	}
+
+	if pageSize <= 0 || pageSize > MaxPageSize {
+		return pageCursor{}, fmt.Errorf("invalid page size %d: must be between 1 and %d", pageSize, MaxPageSize)
+	}
+	return pageCursor{Page: 1, PerPage: pageSize}, nil
+}
+
+// nextCursor encodes the cursor for nextPage, or returns "" when there is none
+func nextCursor(nextPage, perPage int) string {
+	if nextPage == 0 {
+		return ""
+	}
+
+	data, _ := json.Marshal(pageCursor{Page: nextPage, PerPage: perPage})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses an opaque cursor produced by nextCursor
+func decodeCursor(cursor string) (pageCursor, error) {
+	var page pageCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return page, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return page, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	if page.Page < 1 || page.PerPage < 1 || page.PerPage > MaxPageSize {
+		return page, fmt.Errorf("invalid cursor %q", cursor)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,102 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := nextCursor(3, 50)
+	if cursor == "" {
+		t.Fatal("nextCursor() returned empty cursor for a next page")
+	}
+
+	page, err := decodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if page.Page != 3 || page.PerPage != 50 {
+		t.Errorf("decodeCursor() = %+v, want page 3 with 50 per page", page)
+	}
+
+	if cursor := nextCursor(0, 50); cursor != "" {
+		t.Errorf("nextCursor() on last page = %q, want empty", cursor)
+	}
+}
+
+func TestStartPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		cursor   string
+		pageSize int
+		expected pageCursor
+		wantErr  bool
+	}{
+		{
+			name:     "first page",
+			pageSize: 25,
+			expected: pageCursor{Page: 1, PerPage: 25},
+		},
+		{
+			name:     "cursor overrides page size",
+			cursor:   nextCursor(2, 10),
+			pageSize: 25,
+			expected: pageCursor{Page: 2, PerPage: 10},
+		},
+		{
+			name:     "page size too large",
+			pageSize: MaxPageSize + 1,
+			wantErr:  true,
+		},
+		{
+			name:    "garbage cursor",
+			cursor:  "not-a-cursor",
+			wantErr: true,
+		},
+		{
+			name:    "cursor with invalid page",
+			cursor:  nextCursor(-1, 10),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := startPage(tt.cursor, tt.pageSize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("startPage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && result != tt.expected {
+				t.Errorf("startPage() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestListStarredResourcesPage_Filter(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/user/starred", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+		fmt.Fprint(w, `[
+			{"starred_at": "2024-03-15T09:30:00Z", "repo": {"full_name": "owner/old", "name": "old", "owner": {"login": "owner"}, "archived": true}},
+			{"starred_at": "2024-03-14T09:30:00Z", "repo": {"full_name": "owner/live", "name": "live", "owner": {"login": "owner"}}}
+		]`)
+	})
+	adapter := newTestAdapter(t, mux)
+
+	archived := false
+	page, err := adapter.ListStarredResourcesPage(context.Background(), "", 2, SearchOptions{Archived: &archived})
+	if err != nil {
+		t.Fatalf("ListStarredResourcesPage() error = %v", err)
+	}
+
+	if len(page.Repositories) != 1 || page.Repositories[0].Name != "owner/live" {
+		t.Errorf("Repositories = %+v, want only owner/live", page.Repositories)
+	}
+	// Filtering does not end the listing early
+	if page.NextCursor == "" {
+		t.Error("NextCursor is empty, want a cursor for the next page")
+	}
+}
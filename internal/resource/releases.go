@@ -0,0 +1,246 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+// MaxReleases caps the releases and tags listed for a starred repository
+const MaxReleases = 30
+
+const (
+	// releasesPerRepo is how many recent releases are checked per repository
+	// when scanning the star list for new releases
+	releasesPerRepo = 10
+
+	// releaseScanWorkers bounds concurrent release requests during a scan
+	releaseScanWorkers = 8
+)
+
+// RepoReleases lists releases, and optionally tags, of a starred repository
+type RepoReleases struct {
+	Repository string        `json:"repository"`
+	URI        string        `json:"uri"`
+	Releases   []ReleaseInfo `json:"releases"`
+	Tags       []TagInfo     `json:"tags,omitempty"`
+}
+
+// ReleaseInfo is a published release in MCP output
+type ReleaseInfo struct {
+	Tag         string `json:"tag"`
+	Name        string `json:"name,omitempty"`
+	PublishedAt string `json:"published_at"`
+	Prerelease  bool   `json:"prerelease"`
+	URL         string `json:"url"`
+	Notes       string `json:"notes,omitempty"`
+}
+
+// TagInfo is a git tag in MCP output
+type TagInfo struct {
+	Name      string `json:"name"`
+	CommitSHA string `json:"commit_sha"`
+}
+
+// ReleaseOptions selects starred repositories with releases published since a date
+type ReleaseOptions struct {
+	// Since is required; releases published before it are ignored
+	Since time.Time
+
+	IncludePrereleases bool
+
+	// IncludeNotes adds the release notes to each release
+	IncludeNotes bool
+
+	// Filter narrows which starred repositories are checked; its Sort,
+	// Order and Limit are ignored
+	Filter SearchOptions
+
+	// Limit caps the number of repositories returned; zero returns all
+	Limit int
+}
+
+// GetStarredReleases returns the most recent published releases and tags of a
+// starred repository, up to MaxReleases of each
+func (a *Adapter) GetStarredReleases(ctx context.Context, fullName string) (*RepoReleases, error) {
+	owner, name, err := splitFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.requireStarred(ctx, owner, name); err != nil {
+		return nil, err
+	}
+
+	releases, err := a.clientFor(ctx).GetReleases(ctx, owner, name, MaxReleases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get releases of %s: %w", fullName, err)
+	}
+
+	tags, err := a.clientFor(ctx).GetTags(ctx, owner, name, MaxReleases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags of %s: %w", fullName, err)
+	}
+
+	result := &RepoReleases{
+		Repository: fullName,
+		URI:        a.uri("starred/" + fullName + "/releases"),
+		Releases:   publishedReleases(releases, time.Time{}, true, true),
+		Tags:       make([]TagInfo, 0, len(tags)),
+	}
+	for _, tag := range tags {
+		result.Tags = append(result.Tags, TagInfo{Name: tag.Name, CommitSHA: tag.CommitSHA})
+	}
+
+	return result, nil
+}
+
+// ReleasesSince returns the starred repositories that published a release
+// since opts.Since, most recent release first, along with the total number of
+// such repositories before the limit is applied. Only the latest few releases
+// of each repository are checked, one request per starred repository, so
+// narrowing the star list with opts.Filter keeps scans fast.
+func (a *Adapter) ReleasesSince(ctx context.Context, opts ReleaseOptions) ([]RepoReleases, int, error) {
+	if opts.Since.IsZero() {
+		return nil, 0, fmt.Errorf("since date is required")
+	}
+	if err := opts.Filter.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if opts.Limit < 0 {
+		return nil, 0, fmt.Errorf("invalid limit %d: must not be negative", opts.Limit)
+	}
+
+	repos, err := a.clientFor(ctx).GetStarredRepos(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get starred repos: %w", err)
+	}
+
+	filter := opts.Filter
+	filter.Sort, filter.Order, filter.Limit = "", "", 0
+	candidates := searchRepos(repos, filter)
+
+	found, err := a.scanReleases(ctx, candidates, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// RFC 3339 timestamps in UTC sort lexically
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Releases[0].PublishedAt > found[j].Releases[0].PublishedAt
+	})
+
+	total := len(found)
+	if opts.Limit > 0 && len(found) > opts.Limit {
+		found = found[:opts.Limit]
+	}
+	return found, total, nil
+}
+
+// scanReleases fetches the recent releases of repos concurrently and keeps
+// the repositories with releases matching opts
+func (a *Adapter) scanReleases(ctx context.Context, repos []github.StarredRepo, opts ReleaseOptions) ([]RepoReleases, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]RepoReleases, len(repos))
+	sem := make(chan struct{}, releaseScanWorkers)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var scanErr error
+
+	for i, repo := range repos {
+		owner, name, err := splitFullName(repo.FullName)
+		if err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			releases, err := a.clientFor(ctx).GetReleases(ctx, owner, name, releasesPerRepo)
+			if err != nil {
+				if errors.Is(err, github.ErrNotFound) {
+					return
+				}
+				once.Do(func() {
+					scanErr = fmt.Errorf("failed to get releases of %s: %w", repo.FullName, err)
+					cancel()
+				})
+				return
+			}
+
+			results[i] = RepoReleases{
+				Repository: repo.FullName,
+				URI:        a.uri("starred/" + repo.FullName + "/releases"),
+				Releases:   publishedReleases(releases, opts.Since, opts.IncludePrereleases, opts.IncludeNotes),
+			}
+		}()
+	}
+	wg.Wait()
+
+	if scanErr != nil {
+		return nil, scanErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	found := make([]RepoReleases, 0)
+	for _, result := range results {
+		if len(result.Releases) > 0 {
+			found = append(found, result)
+		}
+	}
+	return found, nil
+}
+
+// publishedReleases converts releases published on or after since, newest
+// first, skipping drafts and, unless includePrereleases is set, prereleases
+func publishedReleases(releases []github.Release, since time.Time, includePrereleases, includeNotes bool) []ReleaseInfo {
+	result := make([]ReleaseInfo, 0, len(releases))
+	for _, release := range releases {
+		if release.Draft || release.PublishedAt == "" {
+			continue
+		}
+		if release.Prerelease && !includePrereleases {
+			continue
+		}
+		if !since.IsZero() {
+			published, err := time.Parse(time.RFC3339, release.PublishedAt)
+			if err != nil || published.Before(since) {
+				continue
+			}
+		}
+
+		info := ReleaseInfo{
+			Tag:         release.TagName,
+			Name:        release.Name,
+			PublishedAt: release.PublishedAt,
+			Prerelease:  release.Prerelease,
+			URL:         release.HTMLURL,
+		}
+		if includeNotes {
+			info.Notes = release.Body
+		}
+		result = append(result, info)
+	}
+
+	// GitHub orders releases by creation; list the latest published first
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].PublishedAt > result[j].PublishedAt
+	})
+	return result
+}
@@ -0,0 +1,70 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+func TestPublishedReleases(t *testing.T) {
+	releases := []github.Release{
+		{TagName: "v1.1.0-rc1", PublishedAt: "2024-06-20T00:00:00Z", Prerelease: true},
+		{TagName: "v1.2.0", Draft: true},
+		{TagName: "v1.0.1", PublishedAt: "2024-06-25T00:00:00Z", Body: "fixes"},
+		{TagName: "v1.0.0", PublishedAt: "2024-05-01T00:00:00Z", Body: "first"},
+	}
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		since              time.Time
+		includePrereleases bool
+		expected           []string
+	}{
+		{
+			name:     "since date without prereleases",
+			since:    since,
+			expected: []string{"v1.0.1"},
+		},
+		{
+			name:               "since date with prereleases",
+			since:              since,
+			includePrereleases: true,
+			expected:           []string{"v1.0.1", "v1.1.0-rc1"},
+		},
+		{
+			name:               "no date bound",
+			includePrereleases: true,
+			expected:           []string{"v1.0.1", "v1.1.0-rc1", "v1.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := publishedReleases(releases, tt.since, tt.includePrereleases, false)
+
+			tags := make([]string, 0, len(result))
+			for _, release := range result {
+				tags = append(tags, release.Tag)
+				if release.Notes != "" {
+					t.Errorf("release %s has notes, want none", release.Tag)
+				}
+			}
+			if len(tags) != len(tt.expected) {
+				t.Fatalf("publishedReleases() = %v, want %v", tags, tt.expected)
+			}
+			for i := range tags {
+				if tags[i] != tt.expected[i] {
+					t.Errorf("publishedReleases() = %v, want %v", tags, tt.expected)
+					break
+				}
+			}
+		})
+	}
+
+	withNotes := publishedReleases(releases, since, false, true)
+	if len(withNotes) != 1 || withNotes[0].Notes != "fixes" {
+		t.Errorf("publishedReleases() with notes = %+v, want v1.0.1 with notes", withNotes)
+	}
+}
@@ -0,0 +1,291 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+// Sort fields accepted by SearchOptions
+const (
+	SortByStars   = "stars"
+	SortByForks   = "forks"
+	SortByUpdated = "updated"
+	SortByPushed  = "pushed"
+	SortByName    = "name"
+	SortByStarred = "starred"
+)
+
+// Sort directions accepted by SearchOptions
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// SearchOptions filters and orders starred repositories.
+// Zero values disable the corresponding filter.
+type SearchOptions struct {
+	// Query is matched case-insensitively against name, full name and description
+	Query string
+
+	// Language is matched case-insensitively against the primary language
+	Language string
+
+	MinStars     int
+	Owner        string
+	UpdatedSince time.Time
+
+	// Topic, License and Visibility are matched case-insensitively;
+	// License is an SPDX identifier such as MIT
+	Topic      string
+	License    string
+	Visibility string
+
+	// Archived, Fork and Template restrict results to repositories with
+	// (true) or without (false) the flag; nil disables the filter
+	Archived *bool
+	Fork     *bool
+	Template *bool
+
+	// StarredSince and StarredUntil bound, inclusively, when the repository was starred
+	StarredSince time.Time
+	StarredUntil time.Time
+
+	// Sort is one of the SortBy constants; empty keeps the order in which
+	// repositories were starred (most recent first)
+	Sort string
+
+	// Order is OrderAsc or OrderDesc; empty sorts names ascending and
+	// numbers and dates descending
+	Order string
+
+	// Limit caps the number of results; zero returns all matches
+	Limit int
+}
+
+// Validate checks that the sort field and direction are supported
+func (o SearchOptions) Validate() error {
+	switch o.Sort {
+	case "", SortByStars, SortByForks, SortByUpdated, SortByPushed, SortByName, SortByStarred:
+	default:
+		return fmt.Errorf("invalid sort %q: must be one of %s, %s, %s, %s, %s, %s",
+			o.Sort, SortByStars, SortByForks, SortByUpdated, SortByPushed, SortByName, SortByStarred)
+	}
+
+	switch o.Order {
+	case "", OrderAsc, OrderDesc:
+	default:
+		return fmt.Errorf("invalid order %q: must be %s or %s", o.Order, OrderAsc, OrderDesc)
+	}
+
+	if o.MinStars < 0 {
+		return fmt.Errorf("invalid min_stars %d: must not be negative", o.MinStars)
+	}
+	if !o.StarredSince.IsZero() && !o.StarredUntil.IsZero() && o.StarredSince.After(o.StarredUntil) {
+		return fmt.Errorf("invalid star date range: %s is after %s",
+			o.StarredSince.Format(time.RFC3339), o.StarredUntil.Format(time.RFC3339))
+	}
+	if o.Limit < 0 {
+		return fmt.Errorf("invalid limit %d: must not be negative", o.Limit)
+	}
+
+	return nil
+}
+
+// SearchStarredResources returns the starred repositories matching opts as MCP
+// resources, along with the total number of matches before the limit is applied
+func (a *Adapter) SearchStarredResources(ctx context.Context, opts SearchOptions) ([]MCPResource, int, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	repos, err := a.clientFor(ctx).GetStarredRepos(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get starred repos: %w", err)
+	}
+
+	matches := searchRepos(repos, opts)
+	total := len(matches)
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+
+	resources := make([]MCPResource, 0, len(matches))
+	for _, repo := range matches {
+		resources = append(resources, a.repoToMCPResource(repo))
+	}
+
+	return resources, total, nil
+}
+
+// RankStarredResources runs a BM25 full-text search over the starred
+// repositories and returns the best matches as MCP resources, each with its
+// relevance score. The index is brought up to date with the star list first.
+func (a *Adapter) RankStarredResources(ctx context.Context, query string, limit int) ([]MCPResource, error) {
+	searchIndex := a.indexFor(ctx)
+	if searchIndex == nil {
+		return nil, fmt.Errorf("full-text search index is not configured")
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query must not be empty")
+	}
+
+	repos, err := a.clientFor(ctx).GetStarredRepos(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get starred repos: %w", err)
+	}
+
+	if err := searchIndex.Update(ctx, repos); err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		// A stale index still gives useful results
+		log.Printf("Failed to update search index: %v", err)
+	}
+
+	byName := make(map[string]github.StarredRepo, len(repos))
+	for _, repo := range repos {
+		byName[repo.FullName] = repo
+	}
+
+	hits := searchIndex.Search(query, limit)
+	resources := make([]MCPResource, 0, len(hits))
+	for _, hit := range hits {
+		repo, ok := byName[hit.FullName]
+		if !ok {
+			continue
+		}
+		resource := a.repoToMCPResource(repo)
+		resource.Contents["score"] = math.Round(hit.Score*1000) / 1000
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// searchRepos filters and sorts repos according to opts
+func searchRepos(repos []github.StarredRepo, opts SearchOptions) []github.StarredRepo {
+	matches := make([]github.StarredRepo, 0, len(repos))
+	for _, repo := range repos {
+		if opts.matches(repo) {
+			matches = append(matches, repo)
+		}
+	}
+
+	sortRepos(matches, opts.Sort, opts.Order)
+	return matches
+}
+
+// matches reports whether repo satisfies every filter in opts
+func (o SearchOptions) matches(repo github.StarredRepo) bool {
+	if o.Query != "" {
+		query := strings.ToLower(o.Query)
+		if !strings.Contains(strings.ToLower(repo.FullName), query) &&
+			!strings.Contains(strings.ToLower(repo.Description), query) {
+			return false
+		}
+	}
+
+	if o.Language != "" && !strings.EqualFold(repo.Language, o.Language) {
+		return false
+	}
+
+	if repo.Stars < o.MinStars {
+		return false
+	}
+
+	if o.Owner != "" && !strings.EqualFold(repo.Owner, o.Owner) {
+		return false
+	}
+
+	if !o.UpdatedSince.IsZero() {
+		updated, err := time.Parse(time.RFC3339, repo.UpdatedAt)
+		if err != nil || updated.Before(o.UpdatedSince) {
+			return false
+		}
+	}
+
+	if o.Topic != "" && !containsFold(repo.Topics, o.Topic) {
+		return false
+	}
+	if o.License != "" && !strings.EqualFold(repo.License, o.License) {
+		return false
+	}
+	if o.Visibility != "" && !strings.EqualFold(repo.Visibility, o.Visibility) {
+		return false
+	}
+
+	if o.Archived != nil && repo.Archived != *o.Archived {
+		return false
+	}
+	if o.Fork != nil && repo.Fork != *o.Fork {
+		return false
+	}
+	if o.Template != nil && repo.IsTemplate != *o.Template {
+		return false
+	}
+
+	if !o.StarredSince.IsZero() || !o.StarredUntil.IsZero() {
+		starred, err := time.Parse(time.RFC3339, repo.StarredAt)
+		if err != nil {
+			return false
+		}
+		if !o.StarredSince.IsZero() && starred.Before(o.StarredSince) {
+			return false
+		}
+		if !o.StarredUntil.IsZero() && starred.After(o.StarredUntil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortRepos orders repos in place. The sort is stable so ties keep star order.
+func sortRepos(repos []github.StarredRepo, sortBy, order string) {
+	var less func(i, j int) bool
+
+	switch sortBy {
+	case SortByStars:
+		less = func(i, j int) bool { return repos[i].Stars < repos[j].Stars }
+	case SortByForks:
+		less = func(i, j int) bool { return repos[i].Forks < repos[j].Forks }
+	case SortByUpdated:
+		// RFC 3339 timestamps in UTC sort lexically
+		less = func(i, j int) bool { return repos[i].UpdatedAt < repos[j].UpdatedAt }
+	case SortByPushed:
+		less = func(i, j int) bool { return repos[i].PushedAt < repos[j].PushedAt }
+	case SortByStarred:
+		less = func(i, j int) bool { return repos[i].StarredAt < repos[j].StarredAt }
+	case SortByName:
+		less = func(i, j int) bool {
+			return strings.ToLower(repos[i].FullName) < strings.ToLower(repos[j].FullName)
+		}
+	default:
+		return
+	}
+
+	descending := order == OrderDesc || (order == "" && sortBy != SortByName)
+	if descending {
+		sort.SliceStable(repos, func(i, j int) bool { return less(j, i) })
+		return
+	}
+	sort.SliceStable(repos, less)
+}
+
+// containsFold reports whether values contains target, ignoring case
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
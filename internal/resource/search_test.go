@@ -0,0 +1,166 @@
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+func testRepos() []github.StarredRepo {
+	return []github.StarredRepo{
+		{Name: "mcp-go", FullName: "mark3labs/mcp-go", Description: "Go SDK for MCP", Language: "Go", Stars: 5000, Forks: 400, Owner: "mark3labs", UpdatedAt: "2024-06-01T00:00:00Z", StarredAt: "2024-09-20T10:00:00Z", Topics: []string{"mcp", "golang"}, License: "MIT"},
+		{Name: "react", FullName: "facebook/react", Description: "UI library", Language: "JavaScript", Stars: 200000, Forks: 40000, Owner: "facebook", UpdatedAt: "2024-05-01T00:00:00Z", StarredAt: "2024-09-02T08:00:00Z", Topics: []string{"ui"}, License: "MIT"},
+		{Name: "fx", FullName: "uber-go/fx", Description: "Dependency injection", Language: "Go", Stars: 800, Forks: 250, Owner: "uber-go", UpdatedAt: "2023-01-01T00:00:00Z", StarredAt: "2024-08-31T23:59:59Z", License: "Apache-2.0", Archived: true},
+		{Name: "bazel", FullName: "bazelbuild/bazel", Description: "Build system", Language: "Java", Stars: 20000, Forks: 3800, Owner: "bazelbuild", UpdatedAt: "2024-07-01T00:00:00Z", StarredAt: "2024-10-01T00:00:00Z", License: "Apache-2.0", Fork: true},
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func fullNames(repos []github.StarredRepo) []string {
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.FullName)
+	}
+	return names
+}
+
+func TestSearchRepos(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     SearchOptions
+		expected []string
+	}{
+		{
+			name:     "no filters keeps star order",
+			opts:     SearchOptions{},
+			expected: []string{"mark3labs/mcp-go", "facebook/react", "uber-go/fx", "bazelbuild/bazel"},
+		},
+		{
+			name:     "query matches description case-insensitively",
+			opts:     SearchOptions{Query: "INJECTION"},
+			expected: []string{"uber-go/fx"},
+		},
+		{
+			name:     "language and min stars",
+			opts:     SearchOptions{Language: "go", MinStars: 1000},
+			expected: []string{"mark3labs/mcp-go"},
+		},
+		{
+			name:     "owner",
+			opts:     SearchOptions{Owner: "Facebook"},
+			expected: []string{"facebook/react"},
+		},
+		{
+			name:     "updated since",
+			opts:     SearchOptions{UpdatedSince: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Sort: SortByUpdated},
+			expected: []string{"bazelbuild/bazel", "mark3labs/mcp-go", "facebook/react"},
+		},
+		{
+			name:     "sort by stars defaults to descending",
+			opts:     SearchOptions{Sort: SortByStars},
+			expected: []string{"facebook/react", "bazelbuild/bazel", "mark3labs/mcp-go", "uber-go/fx"},
+		},
+		{
+			name:     "sort by forks ascending",
+			opts:     SearchOptions{Sort: SortByForks, Order: OrderAsc},
+			expected: []string{"uber-go/fx", "mark3labs/mcp-go", "bazelbuild/bazel", "facebook/react"},
+		},
+		{
+			name:     "topic and license",
+			opts:     SearchOptions{Topic: "GoLang", License: "mit"},
+			expected: []string{"mark3labs/mcp-go"},
+		},
+		{
+			name:     "skip archived repositories",
+			opts:     SearchOptions{Archived: boolPtr(false)},
+			expected: []string{"mark3labs/mcp-go", "facebook/react", "bazelbuild/bazel"},
+		},
+		{
+			name:     "only forks",
+			opts:     SearchOptions{Fork: boolPtr(true)},
+			expected: []string{"bazelbuild/bazel"},
+		},
+		{
+			name: "starred within range, newest first",
+			opts: SearchOptions{
+				StarredSince: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+				StarredUntil: time.Date(2024, 9, 30, 23, 59, 59, 0, time.UTC),
+				Sort:         SortByStarred,
+			},
+			expected: []string{"mark3labs/mcp-go", "facebook/react"},
+		},
+		{
+			name:     "sort by starred ascending",
+			opts:     SearchOptions{Sort: SortByStarred, Order: OrderAsc},
+			expected: []string{"uber-go/fx", "facebook/react", "mark3labs/mcp-go", "bazelbuild/bazel"},
+		},
+		{
+			name:     "sort by name defaults to ascending",
+			opts:     SearchOptions{Sort: SortByName},
+			expected: []string{"bazelbuild/bazel", "facebook/react", "mark3labs/mcp-go", "uber-go/fx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := fullNames(searchRepos(testRepos(), tt.opts))
+			if len(result) != len(tt.expected) {
+				t.Fatalf("searchRepos() = %v, want %v", result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Fatalf("searchRepos() = %v, want %v", result, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    SearchOptions
+		wantErr bool
+	}{
+		{
+			name: "valid options",
+			opts: SearchOptions{Sort: SortByStars, Order: OrderAsc, Limit: 10},
+		},
+		{
+			name:    "unknown sort",
+			opts:    SearchOptions{Sort: "popularity"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown order",
+			opts:    SearchOptions{Order: "sideways"},
+			wantErr: true,
+		},
+		{
+			name: "star date range reversed",
+			opts: SearchOptions{
+				StarredSince: time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC),
+				StarredUntil: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "negative min stars",
+			opts:    SearchOptions{MinStars: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+)
+
+// Star actions reported in StarResult
+const (
+	ActionStar   = "star"
+	ActionUnstar = "unstar"
+)
+
+// StarResult describes the outcome of starring or unstarring a repository
+type StarResult struct {
+	Repository string `json:"repository"`
+	Action     string `json:"action"`
+
+	// Starred is the star status after the call; in a dry run it is the
+	// status the call would leave behind
+	Starred bool `json:"starred"`
+
+	// Changed reports whether the star status changed (or, in a dry run, would change)
+	Changed bool `json:"changed"`
+
+	DryRun bool `json:"dry_run"`
+}
+
+// StarRepository stars a repository for the authenticated user. Already
+// starred repositories are left untouched. With dryRun set nothing is changed
+// and the result describes what would happen.
+func (a *Adapter) StarRepository(ctx context.Context, fullName string, dryRun bool) (*StarResult, error) {
+	return a.setStarred(ctx, fullName, true, dryRun)
+}
+
+// UnstarRepository removes the authenticated user's star from a repository.
+// Repositories that are not starred are left untouched. With dryRun set
+// nothing is changed and the result describes what would happen.
+func (a *Adapter) UnstarRepository(ctx context.Context, fullName string, dryRun bool) (*StarResult, error) {
+	return a.setStarred(ctx, fullName, false, dryRun)
+}
+
+// setStarred brings the star status of fullName to starred
+func (a *Adapter) setStarred(ctx context.Context, fullName string, starred, dryRun bool) (*StarResult, error) {
+	owner, name, err := splitFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StarResult{
+		Repository: fullName,
+		Action:     ActionUnstar,
+		Starred:    starred,
+		DryRun:     dryRun,
+	}
+	if starred {
+		result.Action = ActionStar
+	}
+
+	current, err := a.clientFor(ctx).IsStarred(ctx, owner, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get star status of %s: %w", fullName, err)
+	}
+	if current == starred {
+		return result, nil
+	}
+	result.Changed = true
+
+	if dryRun {
+		// GitHub reports unknown repositories as not starred; make sure a
+		// dry run does not promise to star something that does not exist
+		if starred {
+			if _, err := a.clientFor(ctx).GetRepo(ctx, owner, name); err != nil {
+				return nil, fmt.Errorf("failed to get repository %s: %w", fullName, err)
+			}
+		}
+		return result, nil
+	}
+
+	if starred {
+		err = a.clientFor(ctx).StarRepo(ctx, owner, name)
+	} else {
+		err = a.clientFor(ctx).UnstarRepo(ctx, owner, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+// newStarTestAdapter returns an Adapter backed by a GitHub on which
+// owner/starred is starred, owner/unstarred exists without a star and
+// owner/missing does not exist. Star and unstar calls are appended to writes.
+func newStarTestAdapter(t *testing.T, writes *[]string) *Adapter {
+	t.Helper()
+
+	starred := map[string]bool{"starred": true}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/user/starred/owner/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		repo := r.PathValue("repo")
+		switch r.Method {
+		case http.MethodGet:
+			if !starred[repo] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		case http.MethodPut, http.MethodDelete:
+			*writes = append(*writes, r.Method+" "+repo)
+			if repo == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/v3/repos/owner/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		repo := r.PathValue("repo")
+		if repo == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"name": %q, "full_name": "owner/%s", "owner": {"login": "owner"}}`, repo, repo)
+	})
+
+	return newTestAdapter(t, mux)
+}
+
+func TestSetStarred(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		star       bool
+		dryRun     bool
+		expected   StarResult
+		wantWrites []string
+	}{
+		{
+			name:       "star",
+			repository: "owner/unstarred",
+			star:       true,
+			expected:   StarResult{Repository: "owner/unstarred", Action: ActionStar, Starred: true, Changed: true},
+			wantWrites: []string{"PUT unstarred"},
+		},
+		{
+			name:       "unstar",
+			repository: "owner/starred",
+			expected:   StarResult{Repository: "owner/starred", Action: ActionUnstar, Changed: true},
+			wantWrites: []string{"DELETE starred"},
+		},
+		{
+			name:       "already starred",
+			repository: "owner/starred",
+			star:       true,
+			expected:   StarResult{Repository: "owner/starred", Action: ActionStar, Starred: true},
+		},
+		{
+			name:       "not starred",
+			repository: "owner/unstarred",
+			expected:   StarResult{Repository: "owner/unstarred", Action: ActionUnstar},
+		},
+		{
+			name:       "dry run star",
+			repository: "owner/unstarred",
+			star:       true,
+			dryRun:     true,
+			expected:   StarResult{Repository: "owner/unstarred", Action: ActionStar, Starred: true, Changed: true, DryRun: true},
+		},
+		{
+			name:       "dry run unstar",
+			repository: "owner/starred",
+			dryRun:     true,
+			expected:   StarResult{Repository: "owner/starred", Action: ActionUnstar, Changed: true, DryRun: true},
+		},
+		{
+			name:       "dry run already starred",
+			repository: "owner/starred",
+			star:       true,
+			dryRun:     true,
+			expected:   StarResult{Repository: "owner/starred", Action: ActionStar, Starred: true, DryRun: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var writes []string
+			adapter := newStarTestAdapter(t, &writes)
+
+			result, err := adapter.setStarred(context.Background(), tt.repository, tt.star, tt.dryRun)
+			if err != nil {
+				t.Fatalf("setStarred() error = %v", err)
+			}
+
+			if *result != tt.expected {
+				t.Errorf("setStarred() = %+v, want %+v", *result, tt.expected)
+			}
+			if fmt.Sprint(writes) != fmt.Sprint(tt.wantWrites) {
+				t.Errorf("writes = %v, want %v", writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+// TestSetStarred_NonexistentRepository tests that starring a repository that
+// does not exist fails, in a dry run as well, even though GitHub reports it
+// as not starred
+func TestSetStarred_NonexistentRepository(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dry run %v", dryRun), func(t *testing.T) {
+			var writes []string
+			adapter := newStarTestAdapter(t, &writes)
+
+			_, err := adapter.StarRepository(context.Background(), "owner/missing", dryRun)
+			if !errors.Is(err, github.ErrNotFound) {
+				t.Errorf("StarRepository() error = %v, want ErrNotFound", err)
+			}
+			if dryRun && len(writes) != 0 {
+				t.Errorf("dry run wrote %v, want no writes", writes)
+			}
+		})
+	}
+
+	// Unstarring a repository that does not exist is a no-op
+	var writes []string
+	adapter := newStarTestAdapter(t, &writes)
+	result, err := adapter.UnstarRepository(context.Background(), "owner/missing", true)
+	if err != nil {
+		t.Fatalf("UnstarRepository() error = %v", err)
+	}
+	if result.Changed {
+		t.Errorf("UnstarRepository() = %+v, want no change", *result)
+	}
+}
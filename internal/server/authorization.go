@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/timduly4/mcp-server/internal/mcpauth"
+)
+
+// errInsufficientScope is returned when the client's token lacks the scope a request needs
+var errInsufficientScope = errors.New("insufficient scope")
+
+// authorizationOptions enforces the scopes of client tokens on tool calls.
+// Resource reads are checked by the requireResourceScope middleware, and
+// hideToolsByScope keeps the tools a client may not call out of tools/list.
+func authorizationOptions() []server.ServerOption {
+	return []server.ServerOption{
+		server.WithToolHandlerMiddleware(requireToolScope),
+	}
+}
+
+// hideToolsByScope removes the tools the client's token may not call from
+// tools/list. Unlike a tool filter, which makes calls of the hidden tools fail
+// as if they did not exist, it leaves those calls to requireToolScope.
+func hideToolsByScope(ctx context.Context, id any, message *mcp.ListToolsRequest, result *mcp.ListToolsResult) {
+	result.Tools = filterToolsByScope(ctx, result.Tools)
+}
+
+// requireResourceScope rejects resource reads whose token lacks mcpauth.ScopeRead
+func requireResourceScope(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if err := checkScope(ctx, mcpauth.ScopeRead); err != nil {
+			return nil, err
+		}
+		return next(ctx, request)
+	}
+}
+
+// requireToolScope rejects tool calls whose token lacks the scope of the
+// tool with an "insufficient scope" tool error
+func requireToolScope(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		scope := mcpauth.ScopeWrite
+		if srv := server.ServerFromContext(ctx); srv != nil {
+			if tool := srv.GetTool(request.Params.Name); tool != nil {
+				scope = toolScope(tool.Tool)
+			}
+		}
+
+		// A tool error result, unlike a JSON-RPC error, tells the client
+		// the call was refused rather than that the server failed
+		if err := checkScope(ctx, scope); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return next(ctx, request)
+	}
+}
+
+// filterToolsByScope lists only the tools the client's token may call
+func filterToolsByScope(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	allowed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if checkScope(ctx, toolScope(tool)) == nil {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// toolScope returns the scope needed to call tool: read-only tools need
+// mcpauth.ScopeRead, every other tool mcpauth.ScopeWrite
+func toolScope(tool mcp.Tool) string {
+	if readOnly := tool.Annotations.ReadOnlyHint; readOnly != nil && *readOnly {
+		return mcpauth.ScopeRead
+	}
+	return mcpauth.ScopeWrite
+}
+
+// checkScope reports an error unless the request was authorized by a token granting scope
+func checkScope(ctx context.Context, scope string) error {
+	claims, ok := mcpauth.ClaimsFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("%w: request carries no access token", errInsufficientScope)
+	}
+	if !claims.HasScope(scope) {
+		return fmt.Errorf("%w: %s is required", errInsufficientScope, scope)
+	}
+	return nil
+}
+
+// authorizedHandler wraps the handler of an HTTP-based transport for client
+// authorization: it serves the protected resource metadata (RFC 9728) and
+// passes everything else to handler once a valid bearer token is presented
+func (m *MCPServer) authorizedHandler(handler http.Handler) (http.Handler, error) {
+	verifier, err := m.newVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := server.ProtectedResourceMetadataConfig{
+		Resource:               m.cfg.MCPAuthResource,
+		ScopesSupported:        []string{mcpauth.ScopeRead, mcpauth.ScopeWrite},
+		BearerMethodsSupported: []string{"header"},
+		ResourceName:           serverName,
+	}
+	if m.cfg.MCPAuthIssuer != "" {
+		metadata.AuthorizationServers = []string{m.cfg.MCPAuthIssuer}
+	}
+
+	metadataPath := server.ProtectedResourceMetadataPath(metadata.Resource)
+	metadataURL, err := url.Parse(metadata.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource URL %q: %w", metadata.Resource, err)
+	}
+	metadataURL.Path = metadataPath
+	metadataURL.RawQuery = ""
+	metadataURL.Fragment = ""
+
+	mux := http.NewServeMux()
+	mux.Handle(metadataPath, server.NewProtectedResourceMetadataHandler(metadata))
+	mux.Handle("/", mcpauth.Middleware(verifier, metadataURL.String())(handler))
+
+	return mux, nil
+}
+
+// newVerifier returns the verifier of client tokens: static public keys when
+// configured, otherwise the signing keys published by the issuer
+func (m *MCPServer) newVerifier() (*mcpauth.Verifier, error) {
+	var keys mcpauth.KeySet
+	if m.cfg.MCPAuthPublicKeysPath != "" {
+		data, err := os.ReadFile(m.cfg.MCPAuthPublicKeysPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token public keys: %w", err)
+		}
+		static, err := mcpauth.ParsePublicKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load token public keys from %s: %w", m.cfg.MCPAuthPublicKeysPath, err)
+		}
+		keys = static
+	} else {
+		keys = mcpauth.NewIssuerKeys(m.cfg.MCPAuthIssuer, nil)
+	}
+
+	return mcpauth.NewVerifier(m.cfg.MCPAuthIssuer, m.cfg.MCPAuthResource, keys), nil
+}
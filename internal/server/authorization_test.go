@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/timduly4/mcp-server/internal/config"
+	"github.com/timduly4/mcp-server/internal/mcpauth"
+)
+
+// newAuthorizedServer returns a server with write tools and client authorization enabled
+func newAuthorizedServer() *MCPServer {
+	return NewMCPServer(&config.Config{
+		Transport:        config.TransportStreamableHTTP,
+		EnableWriteTools: true,
+		MCPAuthResource:  "https://mcp.example.com/mcp",
+		MCPAuthIssuer:    "https://auth.example.com",
+	}, nil)
+}
+
+// TestToolScopes tests which scope each tool requires and that tools/list
+// only shows the tools a token may call
+func TestToolScopes(t *testing.T) {
+	srv := newAuthorizedServer()
+
+	for name, want := range map[string]string{
+		"search_starred": mcpauth.ScopeRead,
+		"star_history":   mcpauth.ScopeRead,
+		"star_repo":      mcpauth.ScopeWrite,
+		"unstar_repo":    mcpauth.ScopeWrite,
+	} {
+		if scope := toolScope(srv.server.GetTool(name).Tool); scope != want {
+			t.Errorf("toolScope(%s) = %s, want %s", name, scope, want)
+		}
+	}
+
+	var tools []mcp.Tool
+	for _, name := range []string{"search_starred", "star_repo"} {
+		tools = append(tools, srv.server.GetTool(name).Tool)
+	}
+
+	tests := []struct {
+		name   string
+		scopes []string
+		want   []string
+	}{
+		{name: "read only", scopes: []string{mcpauth.ScopeRead}, want: []string{"search_starred"}},
+		{name: "read and write", scopes: []string{mcpauth.ScopeRead, mcpauth.ScopeWrite}, want: []string{"search_starred", "star_repo"}},
+		{name: "no scopes", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := mcpauth.WithClaims(context.Background(), &mcpauth.Claims{Scopes: tt.scopes})
+
+			var names []string
+			for _, tool := range filterToolsByScope(ctx, tools) {
+				names = append(names, tool.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("filterToolsByScope() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+	// tools/list goes through hideToolsByScope
+	ctx := mcpauth.WithClaims(context.Background(), &mcpauth.Claims{Scopes: []string{mcpauth.ScopeRead}})
+	response := srv.server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	result, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("tools/list = %#v, want a result", response)
+	}
+	for _, tool := range result.Result.(mcp.ListToolsResult).Tools {
+		if toolScope(tool) != mcpauth.ScopeRead {
+			t.Errorf("tools/list with read scope lists %s", tool.Name)
+		}
+	}
+}
+
+// TestRequireToolScope tests that tool calls without the tool's scope never reach the handler
+func TestRequireToolScope(t *testing.T) {
+	srv := newAuthorizedServer()
+	ctx := mcpauth.WithClaims(context.Background(), &mcpauth.Claims{Scopes: []string{mcpauth.ScopeRead}})
+
+	response := srv.server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"star_repo","arguments":{"repository":"mark3labs/mcp-go"}}}`))
+	result, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("tools/call star_repo with read scope = %#v, want a tool result", response)
+	}
+	toolResult, ok := result.Result.(*mcp.CallToolResult)
+	if !ok || !toolResult.IsError || len(toolResult.Content) != 1 {
+		t.Fatalf("tools/call star_repo with read scope = %#v, want a tool error", result.Result)
+	}
+	if text, _ := toolResult.Content[0].(mcp.TextContent); !strings.Contains(text.Text, errInsufficientScope.Error()) {
+		t.Errorf("tool error = %q, want it to report the insufficient scope", text.Text)
+	}
+}
+
+// TestRequireResourceScope tests that resource reads need the read scope
+func TestRequireResourceScope(t *testing.T) {
+	handler := requireResourceScope(func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr bool
+	}{
+		{
+			name: "read scope",
+			ctx:  mcpauth.WithClaims(context.Background(), &mcpauth.Claims{Scopes: []string{mcpauth.ScopeRead}}),
+		},
+		{
+			name:    "write scope only",
+			ctx:     mcpauth.WithClaims(context.Background(), &mcpauth.Claims{Scopes: []string{mcpauth.ScopeWrite}}),
+			wantErr: true,
+		},
+		{
+			name:    "no token",
+			ctx:     context.Background(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(tt.ctx, mcp.ReadResourceRequest{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handler error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errInsufficientScope) {
+				t.Errorf("handler error = %v, want errInsufficientScope", err)
+			}
+		})
+	}
+}
+
+// TestAuthorizedHandler tests that the protected resource metadata is public
+// and everything else requires a bearer token
+func TestAuthorizedHandler(t *testing.T) {
+	srv := newAuthorizedServer()
+	handler, err := srv.authorizedHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request to %s reached the transport without a token", r.URL.Path)
+	}))
+	if err != nil {
+		t.Fatalf("authorizedHandler() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/oauth-protected-resource/mcp", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("metadata status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("/mcp status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	want := `Bearer resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource/mcp"`
+	if challenge := rec.Header().Get("WWW-Authenticate"); challenge != want {
+		t.Errorf("WWW-Authenticate = %q, want %q", challenge, want)
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// readAheadField is the request metadata field that carries a read made by
+// the before-read hook to the handler mcp-go then calls
+const readAheadField = "github-mcp-server/read-ahead"
+
+// readAhead is the outcome of a resource read made by the before-read hook
+type readAhead struct {
+	contents []mcp.ResourceContents
+	err      error
+}
+
+// fallibleTemplate is a resource template whose resources may not exist
+type fallibleTemplate struct {
+	template *mcp.URITemplate
+	handler  server.ResourceHandlerFunc
+}
+
+// missingResources reports reads of resources that do not exist, such as a
+// repository that is not starred, as RESOURCE_NOT_FOUND. mcp-go answers
+// every resource handler error with INTERNAL_ERROR and sends
+// RESOURCE_NOT_FOUND only when no resource or template matches the URI. So
+// the handlers of fallible templates run early, in the before-read hook:
+// when the resource is missing the hook points the request at a URI that no
+// template matches, and otherwise it hands the result to the handler.
+type missingResources struct {
+	templates []fallibleTemplate
+
+	// middleware is the resource handler middleware of the server, which the
+	// early reads go through as well
+	middleware []server.ResourceHandlerMiddleware
+}
+
+func newMissingResources(middleware ...server.ResourceHandlerMiddleware) *missingResources {
+	return &missingResources{middleware: middleware}
+}
+
+// handler registers a fallible template and returns the handler to add it
+// with, which serves the read the hook made
+func (r *missingResources) handler(template mcp.ResourceTemplate, handler server.ResourceTemplateHandlerFunc) server.ResourceTemplateHandlerFunc {
+	final := server.ResourceHandlerFunc(handler)
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		final = r.middleware[i](final)
+	}
+	r.templates = append(r.templates, fallibleTemplate{template: template.URITemplate, handler: final})
+
+	return server.ResourceTemplateHandlerFunc(readAheadHandler(server.ResourceHandlerFunc(handler)))
+}
+
+// readAheadHandler returns a handler that serves the read the hook made, and
+// calls handler only when there was none. Resources whose URIs a fallible
+// template matches, such as the repositories listed by resources/list, must
+// be added with it so that a read does not reach GitHub twice.
+func readAheadHandler(handler server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if request.Params.Meta != nil {
+			if read, ok := request.Params.Meta.AdditionalFields[readAheadField].(*readAhead); ok {
+				return read.contents, read.err
+			}
+		}
+		return handler(ctx, request)
+	}
+}
+
+// beforeRead reads resources of fallible templates ahead of mcp-go
+func (r *missingResources) beforeRead(ctx context.Context, id any, request *mcp.ReadResourceRequest) {
+	for _, t := range r.templates {
+		if !t.template.Regexp().MatchString(request.Params.URI) {
+			continue
+		}
+
+		contents, err := t.handler(ctx, *request)
+		if errors.Is(err, mcp.ErrResourceNotFound) {
+			// URIs contain no spaces, so no template matches this one
+			request.Params.URI = fmt.Sprintf("%s (%v)", request.Params.URI, err)
+			return
+		}
+
+		if request.Params.Meta == nil {
+			request.Params.Meta = &mcp.Meta{}
+		}
+		if request.Params.Meta.AdditionalFields == nil {
+			request.Params.Meta.AdditionalFields = make(map[string]any)
+		}
+		request.Params.Meta.AdditionalFields[readAheadField] = &readAhead{contents: contents, err: err}
+		return
+	}
+}
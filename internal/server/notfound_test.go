@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/timduly4/mcp-server/internal/config"
+	"github.com/timduly4/mcp-server/internal/github"
+	"github.com/timduly4/mcp-server/internal/resource"
+)
+
+// TestMissingResources tests the JSON-RPC error code clients receive for
+// reads of resources that do not exist
+func TestMissingResources(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/user/starred/owner/unstarred", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/api/v3/user/starred/owner/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/api/v3/user/starred/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/v3/repos/owner/repo/readme", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	releaseReads := 0
+	mux.HandleFunc("/api/v3/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		releaseReads++
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/api/v3/repos/owner/repo/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name":"v1.0.0"}]`)
+	})
+	gh := httptest.NewServer(mux)
+	defer gh.Close()
+
+	client, err := github.NewClient(context.Background(), "test-token",
+		github.WithEnterpriseURLs(gh.URL, ""), github.WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	srv := NewMCPServer(&config.Config{}, resource.NewAdapter(client, nil))
+
+	tests := []struct {
+		name     string
+		uri      string
+		wantCode int // 0 for success
+	}{
+		{name: "repository not starred", uri: "github://starred/owner/unstarred", wantCode: mcp.RESOURCE_NOT_FOUND},
+		{name: "README of a repository not starred", uri: "github://starred/owner/unstarred/readme", wantCode: mcp.RESOURCE_NOT_FOUND},
+		{name: "repository without README", uri: "github://starred/owner/repo/readme", wantCode: mcp.RESOURCE_NOT_FOUND},
+		{name: "GitHub failure", uri: "github://starred/owner/broken/readme", wantCode: mcp.INTERNAL_ERROR},
+		{name: "existing resource", uri: "github://starred/owner/repo/releases"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := json.Marshal(map[string]any{
+				"jsonrpc": mcp.JSONRPC_VERSION,
+				"id":      i,
+				"method":  string(mcp.MethodResourcesRead),
+				"params":  map[string]any{"uri": tt.uri},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			switch response := srv.server.HandleMessage(context.Background(), message).(type) {
+			case mcp.JSONRPCError:
+				if response.Error.Code != tt.wantCode {
+					t.Errorf("error code = %d (%s), want %d", response.Error.Code, response.Error.Message, tt.wantCode)
+				}
+			case mcp.JSONRPCResponse:
+				if tt.wantCode != 0 {
+					t.Errorf("read succeeded, want error code %d", tt.wantCode)
+				}
+				result, ok := response.Result.(mcp.ReadResourceResult)
+				if !ok || len(result.Contents) != 1 {
+					t.Errorf("result = %#v, want one content", response.Result)
+				}
+			default:
+				t.Fatalf("unexpected response %#v", response)
+			}
+		})
+	}
+
+	// The read made ahead of mcp-go is not repeated by the handler
+	if releaseReads != 1 {
+		t.Errorf("releases read %d times, want 1", releaseReads)
+	}
+}
+
+// TestMissingResources_ListedRepository tests that reading a repository
+// registered for resources/list reaches GitHub once, not again after the
+// read made ahead of mcp-go
+func TestMissingResources_ListedRepository(t *testing.T) {
+	var starChecks, repoReads int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/user/starred/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		starChecks++
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/v3/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		repoReads++
+		fmt.Fprint(w, `{"name": "repo", "full_name": "owner/repo", "owner": {"login": "owner"}}`)
+	})
+	gh := httptest.NewServer(mux)
+	defer gh.Close()
+
+	client, err := github.NewClient(context.Background(), "test-token",
+		github.WithEnterpriseURLs(gh.URL, ""), github.WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	srv := NewMCPServer(&config.Config{}, resource.NewAdapter(client, nil))
+	srv.registerRepoResources([]resource.MCPResource{
+		{URI: "github://starred/owner/repo", Name: "owner/repo", MimeType: "application/json"},
+	})
+
+	response := srv.server.HandleMessage(context.Background(),
+		[]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"github://starred/owner/repo"}}`))
+	if _, ok := response.(mcp.JSONRPCResponse); !ok {
+		t.Fatalf("unexpected response %#v", response)
+	}
+
+	if starChecks != 1 || repoReads != 1 {
+		t.Errorf("read made %d star checks and %d repository reads, want 1 each", starChecks, repoReads)
+	}
+}
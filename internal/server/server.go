@@ -2,103 +2,375 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/timduly4/mcp-server/internal/config"
+	"github.com/timduly4/mcp-server/internal/github"
 	"github.com/timduly4/mcp-server/internal/resource"
 )
 
+// serverName is the name the server reports to clients
+const serverName = "GitHub Starred Repos MCP Server"
+
+// httpTransport is implemented by the SSE and streamable HTTP servers
+type httpTransport interface {
+	Start(addr string) error
+	Shutdown(ctx context.Context) error
+}
+
 // MCPServer wraps the MCP server functionality
 type MCPServer struct {
 	server  *server.MCPServer
 	adapter *resource.Adapter
+	cfg     *config.Config
+
+	// uriBase prefixes every resource URI, see resource.URIBase
+	uriBase string
+
+	// tenants holds the GitHub client of each client token in multi-tenant mode
+	tenants *tenants
+
+	mu        sync.Mutex
+	transport httpTransport
+
+	// stopping is set once Shutdown is called
+	stopping bool
+
+	// repoURIs are the individual repository resources currently registered
+	// for resources/list
+	repoURIs map[string]bool
+
+	// stars holds the last polled star list per username ("" for the
+	// authenticated user), used to detect added and removed stars
+	stars map[string]map[string]bool
+
+	subs *subscriptions
+
+	// missing reports reads of resources that do not exist as RESOURCE_NOT_FOUND
+	missing *missingResources
 }
 
 // NewMCPServer creates a new MCP server instance
-func NewMCPServer(adapter *resource.Adapter) *MCPServer {
+func NewMCPServer(cfg *config.Config, adapter *resource.Adapter, options ...Option) *MCPServer {
+	uriBase := resource.URIBase(cfg.GitHubHost())
+	subs := newSubscriptions(uriBase)
+
+	// Fallible templates are read through the same middleware ahead of
+	// mcp-go, see missingResources
+	middleware := []server.ResourceHandlerMiddleware{hostURIs(uriBase), requestTimeout(cfg.RequestTimeout)}
+	if cfg.MCPAuthEnabled() {
+		middleware = append(middleware, requireResourceScope)
+	}
+	missing := newMissingResources(middleware...)
+
+	hooks := subs.hooks()
+	hooks.AddBeforeReadResource(missing.beforeRead)
+	if cfg.MCPAuthEnabled() {
+		hooks.AddAfterListTools(hideToolsByScope)
+	}
+
+	opts := []server.ServerOption{
+		server.WithResourceCapabilities(true, true), // subscribe, listChanged
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(toolRequestTimeout(cfg.RequestTimeout)),
+		server.WithToolCapabilities(false),
+		server.WithPromptCapabilities(false),
+	}
+	for _, mw := range middleware {
+		opts = append(opts, server.WithResourceHandlerMiddleware(mw))
+	}
+	if cfg.PageSize > 0 {
+		opts = append(opts, server.WithPaginationLimit(cfg.PageSize))
+	}
+	if cfg.MCPAuthEnabled() {
+		opts = append(opts, authorizationOptions()...)
+	}
+
 	// Create MCP server with metadata
 	s := server.NewMCPServer(
-		"GitHub Starred Repos MCP Server",
+		serverName,
 		"1.0.0",
-		server.WithResourceCapabilities(true, false), // subscribe = false
-		server.WithToolCapabilities(false),
-		server.WithPromptCapabilities(false),
+		opts...,
 	)
 
 	mcpServer := &MCPServer{
 		server:  s,
 		adapter: adapter,
+		cfg:     cfg,
+		uriBase: uriBase,
+		stars:   make(map[string]map[string]bool),
+		subs:    subs,
+		missing: missing,
+	}
+	for _, option := range options {
+		option(mcpServer)
 	}
 
-	// Register resources
+	// Register resources and tools
 	mcpServer.registerResources()
+	mcpServer.registerTools()
 
 	return mcpServer
 }
 
 // registerResources sets up all MCP resource endpoints
 func (m *MCPServer) registerResources() {
-	// Static resource: List all starred repositories
+	// Static resource: First page of the starred repositories
 	starredListResource := mcp.NewResource(
-		"github://starred",
+		m.uri("starred"),
 		"All Starred Repositories",
 		mcp.WithMIMEType("application/json"),
-		mcp.WithResourceDescription("List of all GitHub repositories starred by the authenticated user"),
+		mcp.WithResourceDescription("GitHub repositories starred by the authenticated user, most recent first. "+
+			"Returns one page; follow next_cursor via "+m.uri("starred?cursor={cursor}")+" for the rest."),
 	)
 
 	m.server.AddResource(starredListResource, m.handleListStarred)
 
-	// Dynamic resource template: Starred repositories for a specific user
-	// NOTE: Register this BEFORE the more general {owner}/{repo} pattern to avoid routing conflicts
+	// Dynamic resource template: Further pages of the starred repositories
+	starredPageTemplate := mcp.NewResourceTemplate(
+		m.uri("starred{?cursor,limit,topic,license,visibility,archived,fork,template}"),
+		"Starred Repositories Page",
+		mcp.WithTemplateMIMEType("application/json"),
+		mcp.WithTemplateDescription("A page of starred repositories, optionally filtered by topic, license, visibility and the archived, fork and template flags; pass the next_cursor of the previous page as cursor"),
+	)
+
+	m.server.AddResourceTemplate(starredPageTemplate, m.handleListStarred)
+
+	// Static resource: Remaining GitHub API quota
+	rateLimitResource := mcp.NewResource(
+		m.uri("ratelimit"),
+		"GitHub API Rate Limit",
+		mcp.WithMIMEType("application/json"),
+		mcp.WithResourceDescription("Remaining GitHub API quota and reset times, for planning expensive calls"),
+	)
+
+	m.server.AddResource(rateLimitResource, m.handleRateLimit)
+
+	// Static resource: The GitHub account and token scopes in use
+	whoamiResource := mcp.NewResource(
+		m.uri("whoami"),
+		"GitHub Identity",
+		mcp.WithMIMEType("application/json"),
+		mcp.WithResourceDescription("The GitHub login the server acts as, with the token's scopes and expiry"),
+	)
+
+	m.server.AddResource(whoamiResource, m.handleWhoami)
+
+	// Dynamic resource template: Ranked full-text search over starred repositories
+	starredSearchTemplate := mcp.NewResourceTemplate(
+		m.uri("starred/search{?q,limit}"),
+		"Search Starred Repositories",
+		mcp.WithTemplateMIMEType("application/json"),
+		mcp.WithTemplateDescription("Starred repositories matching the query q, ranked by relevance"),
+	)
+
+	m.server.AddResourceTemplate(starredSearchTemplate, m.handleSearchStarredResource)
+
+	// Dynamic resource template: Repositories starred within a date range
+	starHistoryTemplate := mcp.NewResourceTemplate(
+		m.uri("starred/history{?since,until,limit}"),
+		"Star History",
+		mcp.WithTemplateMIMEType("application/json"),
+		mcp.WithTemplateDescription("Repositories starred between since and until (YYYY-MM-DD or RFC 3339), newest first"),
+	)
+
+	m.server.AddResourceTemplate(starHistoryTemplate, m.handleStarHistoryResource)
+
+	// Dynamic resource template: Starred repositories for a specific user.
+	// github://starred/users/{username} also matches {owner}/{repo}, and
+	// templates are not matched in registration order, so handleGetStarredRepo
+	// hands such URIs back to handleListUserStarred.
 	userStarredTemplate := mcp.NewResourceTemplate(
-		"github://starred/users/{username}",
+		m.uri("starred/users/{username}{?cursor,limit,topic,license,visibility,archived,fork,template}"),
 		"User Starred Repositories",
 		mcp.WithTemplateMIMEType("application/json"),
-		mcp.WithTemplateDescription("List of all GitHub repositories starred by a specific user"),
+		mcp.WithTemplateDescription("GitHub repositories starred by a specific user, one page at a time, filtered like the starred listing"),
 	)
 
 	m.server.AddResourceTemplate(userStarredTemplate, m.handleListUserStarred)
 
 	// Dynamic resource template: Individual starred repository
 	starredRepoTemplate := mcp.NewResourceTemplate(
-		"github://starred/{owner}/{repo}",
+		m.uri("starred/{owner}/{repo}"),
 		"Starred Repository Details",
 		mcp.WithTemplateMIMEType("application/json"),
 		mcp.WithTemplateDescription("Details of a specific starred repository"),
 	)
 
-	m.server.AddResourceTemplate(starredRepoTemplate, m.handleGetStarredRepo)
+	m.server.AddResourceTemplate(starredRepoTemplate, m.missing.handler(starredRepoTemplate, m.handleGetStarredRepo))
+
+	// Dynamic resource template: README of a starred repository
+	starredReadmeTemplate := mcp.NewResourceTemplate(
+		m.uri("starred/{owner}/{repo}/readme"),
+		"Starred Repository README",
+		mcp.WithTemplateMIMEType("text/markdown"),
+		mcp.WithTemplateDescription("README of a specific starred repository as markdown, for understanding what the project does"),
+	)
+
+	m.server.AddResourceTemplate(starredReadmeTemplate, m.missing.handler(starredReadmeTemplate, m.handleGetStarredReadme))
+
+	// Dynamic resource template: file listing of a starred repository
+	starredTreeTemplate := mcp.NewResourceTemplate(
+		m.uri("starred/{owner}/{repo}/tree/{ref}"),
+		"Starred Repository Tree",
+		mcp.WithTemplateMIMEType("application/json"),
+		mcp.WithTemplateDescription("Recursive list of paths in a starred repository at a branch, tag or commit, with a blob URI for each file; write slashes in the ref as %2F"),
+	)
+
+	m.server.AddResourceTemplate(starredTreeTemplate, m.missing.handler(starredTreeTemplate, m.handleGetStarredTree))
+
+	// Dynamic resource template: file contents of a starred repository
+	starredBlobTemplate := mcp.NewResourceTemplate(
+		m.uri("starred/{owner}/{repo}/blob/{ref}/{+path}"),
+		"Starred Repository File",
+		mcp.WithTemplateDescription("Contents of a file in a starred repository at a branch, tag or commit; the ref and path are percent-encoded, with slashes in the ref written as %2F"),
+	)
+
+	m.server.AddResourceTemplate(starredBlobTemplate, m.missing.handler(starredBlobTemplate, m.handleGetStarredBlob))
+
+	// Dynamic resource template: releases and tags of a starred repository
+	starredReleasesTemplate := mcp.NewResourceTemplate(
+		m.uri("starred/{owner}/{repo}/releases"),
+		"Starred Repository Releases",
+		mcp.WithTemplateMIMEType("application/json"),
+		mcp.WithTemplateDescription("Recent published releases, with notes, and tags of a starred repository"),
+	)
+
+	m.server.AddResourceTemplate(starredReleasesTemplate, m.missing.handler(starredReleasesTemplate, m.handleGetStarredReleases))
+
+	// Static resource: the authenticated user's star lists
+	starListsResource := mcp.NewResource(
+		m.uri("starred/lists"),
+		"Star Lists",
+		mcp.WithMIMEType("application/json"),
+		mcp.WithResourceDescription("The lists the authenticated user groups their stars into"),
+	)
+
+	m.server.AddResource(starListsResource, m.handleListStarLists)
+
+	// Dynamic resource template: repositories of a star list
+	starListTemplate := mcp.NewResourceTemplate(
+		m.uri("starred/lists/{slug}"),
+		"Star List",
+		mcp.WithTemplateMIMEType("application/json"),
+		mcp.WithTemplateDescription("The repositories in one of the authenticated user's star lists"),
+	)
+
+	m.server.AddResourceTemplate(starListTemplate, m.missing.handler(starListTemplate, m.handleGetStarList))
 }
 
-// handleListStarred handles requests for all starred repositories
+// uri returns the resource URI for path on this server's GitHub host
+func (m *MCPServer) uri(path string) string {
+	return m.uriBase + path
+}
+
+// hostURIs lets the handlers, which parse github.com resource URIs, serve the
+// URIs of a GitHub Enterprise Server: request URIs are rewritten to the
+// github.com form and the original URI is restored in the response
+func hostURIs(uriBase string) server.ResourceHandlerMiddleware {
+	return func(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+		return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			original := request.Params.URI
+			request.Params.URI = canonicalURI(original, uriBase)
+			if request.Params.URI == original {
+				return next(ctx, request)
+			}
+
+			contents, err := next(ctx, request)
+			for i, content := range contents {
+				switch c := content.(type) {
+				case mcp.TextResourceContents:
+					if c.URI == request.Params.URI {
+						c.URI = original
+					}
+					contents[i] = c
+				case mcp.BlobResourceContents:
+					if c.URI == request.Params.URI {
+						c.URI = original
+					}
+					contents[i] = c
+				}
+			}
+			return contents, err
+		}
+	}
+}
+
+// canonicalURI maps a resource URI under uriBase to its github.com form
+func canonicalURI(uri, uriBase string) string {
+	if uriBase == resource.DefaultURIBase {
+		return uri
+	}
+	if rest, ok := strings.CutPrefix(uri, uriBase); ok {
+		return resource.DefaultURIBase + rest
+	}
+	return uri
+}
+
+// requestTimeout bounds each resource read with the configured timeout.
+// Cancellation from the transport (client disconnects, shutdown) propagates
+// through the same context down to the GitHub client.
+func requestTimeout(timeout time.Duration) server.ResourceHandlerMiddleware {
+	return func(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+		return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			if timeout <= 0 {
+				return next(ctx, request)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+
+// handleListStarred handles requests for a page of the starred repositories
 func (m *MCPServer) handleListStarred(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	log.Printf("Fetching all starred repositories")
+	cursor, limit, err := parsePageURI(request.Params.URI, m.cfg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := parseRepoFilterURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
 
-	resources, err := m.adapter.ListStarredResources()
+	log.Printf("Fetching starred repositories")
+
+	page, err := m.adapter.ListStarredResourcesPage(ctx, cursor, limit, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list starred resources: %w", err)
 	}
 
 	// Convert to JSON
-	jsonData, err := m.adapter.ToJSON(resources)
+	jsonData, err := json.MarshalIndent(page, "", "  ")
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert resources to JSON: %w", err)
+		return nil, fmt.Errorf("failed to marshal resources to JSON: %w", err)
 	}
 
 	// Return as MCP resource contents
 	contents := []mcp.ResourceContents{
 		mcp.TextResourceContents{
-			URI:      "github://starred",
+			URI:      request.Params.URI,
 			MIMEType: "application/json",
 			Text:     string(jsonData),
 		},
 	}
 
-	log.Printf("Returning %d starred repositories", len(resources))
+	log.Printf("Returning %d starred repositories", len(page.Repositories))
 	return contents, nil
 }
 
@@ -109,20 +381,32 @@ func (m *MCPServer) handleGetStarredRepo(ctx context.Context, request mcp.ReadRe
 	log.Printf("Fetching starred repository: %s", request.Params.URI)
 
 	// Parse the URI to extract owner/repo
-	// For simplicity, we'll search through all starred repos
-	// In production, you might want to parse the URI more carefully
 	fullName := extractFullNameFromURI(request.Params.URI)
 	if fullName == "" {
 		return nil, fmt.Errorf("invalid URI format: %s", request.Params.URI)
 	}
 
-	resource, err := m.adapter.GetStarredResource(fullName)
+	// "users" is a reserved name on GitHub, so this is a user's star list
+	if strings.HasPrefix(fullName, "users/") {
+		return m.handleListUserStarred(ctx, request)
+	}
+
+	// Star lists share the two-segment shape of repository URIs, so a
+	// repository owned by a "lists" account is not reachable here
+	if strings.HasPrefix(fullName, "lists/") {
+		return m.handleGetStarList(ctx, request)
+	}
+
+	repoResource, err := m.adapter.GetStarredResource(ctx, fullName)
 	if err != nil {
+		if errors.Is(err, resource.ErrNotStarred) {
+			return nil, fmt.Errorf("%w: %v", mcp.ErrResourceNotFound, err)
+		}
 		return nil, fmt.Errorf("failed to get starred resource: %w", err)
 	}
 
 	// Convert to JSON
-	jsonData, err := json.MarshalIndent(resource, "", "  ")
+	jsonData, err := json.MarshalIndent(repoResource, "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal resource to JSON: %w", err)
 	}
@@ -139,7 +423,201 @@ func (m *MCPServer) handleGetStarredRepo(ctx context.Context, request mcp.ReadRe
 	return contents, nil
 }
 
-// handleListUserStarred handles requests for starred repositories of a specific user
+// handleGetStarredReadme handles requests for the README of a starred repository
+func (m *MCPServer) handleGetStarredReadme(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	log.Printf("Fetching README: %s", request.Params.URI)
+
+	fullName := extractSubresourceFullName(request.Params.URI, "readme")
+	if fullName == "" {
+		return nil, fmt.Errorf("invalid URI format: %s", request.Params.URI)
+	}
+
+	readme, err := m.adapter.GetStarredReadme(ctx, fullName)
+	if err != nil {
+		if errors.Is(err, resource.ErrNotStarred) || errors.Is(err, github.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %v", mcp.ErrResourceNotFound, err)
+		}
+		return nil, fmt.Errorf("failed to get README: %w", err)
+	}
+
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/markdown",
+			Text:     readme,
+		},
+	}
+
+	log.Printf("Returning README of %s (%d bytes)", fullName, len(readme))
+	return contents, nil
+}
+
+// handleGetStarredReleases handles requests for the releases of a starred repository
+func (m *MCPServer) handleGetStarredReleases(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	log.Printf("Fetching releases: %s", request.Params.URI)
+
+	fullName := extractSubresourceFullName(request.Params.URI, "releases")
+	if fullName == "" {
+		return nil, fmt.Errorf("invalid URI format: %s", request.Params.URI)
+	}
+
+	releases, err := m.adapter.GetStarredReleases(ctx, fullName)
+	if err != nil {
+		if errors.Is(err, resource.ErrNotStarred) || errors.Is(err, github.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %v", mcp.ErrResourceNotFound, err)
+		}
+		return nil, fmt.Errorf("failed to get releases: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(releases, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal releases: %w", err)
+	}
+
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}
+
+	log.Printf("Returning %d releases and %d tags of %s", len(releases.Releases), len(releases.Tags), fullName)
+	return contents, nil
+}
+
+// handleListStarLists handles requests for the authenticated user's star lists
+func (m *MCPServer) handleListStarLists(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	log.Printf("Fetching star lists")
+
+	lists, err := m.adapter.ListStarLists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list star lists: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(lists, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal star lists: %w", err)
+	}
+
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}
+
+	log.Printf("Returning %d star lists", len(lists))
+	return contents, nil
+}
+
+// handleGetStarList handles requests for the repositories of a star list
+func (m *MCPServer) handleGetStarList(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	log.Printf("Fetching star list: %s", request.Params.URI)
+
+	slug := extractListSlugFromURI(request.Params.URI)
+	if slug == "" {
+		return nil, fmt.Errorf("invalid URI format: %s", request.Params.URI)
+	}
+
+	list, err := m.adapter.GetStarList(ctx, slug)
+	if err != nil {
+		if errors.Is(err, resource.ErrListNotFound) {
+			return nil, fmt.Errorf("%w: %v", mcp.ErrResourceNotFound, err)
+		}
+		return nil, fmt.Errorf("failed to get star list: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal star list: %w", err)
+	}
+
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}
+
+	log.Printf("Returning %d repositories in star list %s", len(list.Repositories), slug)
+	return contents, nil
+}
+
+// handleGetStarredTree handles requests for the file listing of a starred repository
+func (m *MCPServer) handleGetStarredTree(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	log.Printf("Fetching repository tree: %s", request.Params.URI)
+
+	fullName, ref, _ := parseContentURI(request.Params.URI, "tree")
+	if fullName == "" {
+		return nil, fmt.Errorf("invalid URI format: %s", request.Params.URI)
+	}
+
+	tree, err := m.adapter.GetStarredTree(ctx, fullName, ref)
+	if err != nil {
+		if errors.Is(err, resource.ErrNotStarred) || errors.Is(err, github.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %v", mcp.ErrResourceNotFound, err)
+		}
+		return nil, fmt.Errorf("failed to get repository tree: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tree: %w", err)
+	}
+
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}
+
+	log.Printf("Returning %d paths of %s at %s", len(tree.Entries), fullName, ref)
+	return contents, nil
+}
+
+// handleGetStarredBlob handles requests for a file of a starred repository.
+// Text files are returned as text and anything else base64-encoded.
+func (m *MCPServer) handleGetStarredBlob(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	log.Printf("Fetching repository file: %s", request.Params.URI)
+
+	fullName, ref, filePath := parseContentURI(request.Params.URI, "blob")
+	if fullName == "" || filePath == "" {
+		return nil, fmt.Errorf("invalid URI format: %s", request.Params.URI)
+	}
+
+	file, err := m.adapter.GetStarredFile(ctx, fullName, ref, filePath)
+	if err != nil {
+		if errors.Is(err, resource.ErrNotStarred) || errors.Is(err, github.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %v", mcp.ErrResourceNotFound, err)
+		}
+		return nil, fmt.Errorf("failed to get repository file: %w", err)
+	}
+
+	var contents []mcp.ResourceContents
+	if file.Binary {
+		contents = append(contents, mcp.BlobResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: file.MIMEType,
+			Blob:     base64.StdEncoding.EncodeToString(file.Content),
+		})
+	} else {
+		contents = append(contents, mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: file.MIMEType,
+			Text:     string(file.Content),
+		})
+	}
+
+	log.Printf("Returning %s of %s at %s (%s, %d bytes)", filePath, fullName, ref, file.MIMEType, len(file.Content))
+	return contents, nil
+}
+
+// handleListUserStarred handles requests for a page of the starred repositories of a specific user
 func (m *MCPServer) handleListUserStarred(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 	log.Printf("Fetching starred repositories for user: %s", request.Params.URI)
 
@@ -149,18 +627,58 @@ func (m *MCPServer) handleListUserStarred(ctx context.Context, request mcp.ReadR
 		return nil, fmt.Errorf("invalid URI format: %s", request.Params.URI)
 	}
 
-	resources, err := m.adapter.ListStarredResourcesForUser(username)
+	cursor, limit, err := parsePageURI(request.Params.URI, m.cfg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := parseRepoFilterURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := m.adapter.ListStarredResourcesForUserPage(ctx, username, cursor, limit, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list starred resources for user %s: %w", username, err)
 	}
 
 	// Convert to JSON
+	jsonData, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resources to JSON: %w", err)
+	}
+
+	// Return as MCP resource contents
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}
+
+	log.Printf("Returning %d starred repositories for user %s", len(page.Repositories), username)
+	return contents, nil
+}
+
+// handleSearchStarredResource handles ranked search requests of the form github://starred/search?q=...
+func (m *MCPServer) handleSearchStarredResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	query, limit, err := parseSearchURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Ranked search of starred repositories: %q", query)
+
+	resources, err := m.adapter.RankStarredResources(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search starred resources: %w", err)
+	}
+
 	jsonData, err := m.adapter.ToJSON(resources)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert resources to JSON: %w", err)
 	}
 
-	// Return as MCP resource contents
 	contents := []mcp.ResourceContents{
 		mcp.TextResourceContents{
 			URI:      request.Params.URI,
@@ -169,7 +687,88 @@ func (m *MCPServer) handleListUserStarred(ctx context.Context, request mcp.ReadR
 		},
 	}
 
-	log.Printf("Returning %d starred repositories for user %s", len(resources), username)
+	log.Printf("Returning %d ranked starred repositories", len(resources))
+	return contents, nil
+}
+
+// handleStarHistoryResource handles star history requests of the form github://starred/history?since=...&until=...
+func (m *MCPServer) handleStarHistoryResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	opts, err := parseHistoryURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Listing star history: %+v", opts)
+
+	resources, _, err := m.adapter.SearchStarredResources(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list star history: %w", err)
+	}
+
+	jsonData, err := m.adapter.ToJSON(resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert resources to JSON: %w", err)
+	}
+
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}
+
+	log.Printf("Returning %d starred repositories from star history", len(resources))
+	return contents, nil
+}
+
+// handleRateLimit handles requests for the GitHub API rate limit status
+func (m *MCPServer) handleRateLimit(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	log.Printf("Fetching GitHub rate limit status")
+
+	rateLimit, err := m.adapter.GetRateLimitResource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate limit resource: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(rateLimit, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource to JSON: %w", err)
+	}
+
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}
+
+	return contents, nil
+}
+
+// handleWhoami handles requests for the authenticated GitHub identity
+func (m *MCPServer) handleWhoami(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	log.Printf("Fetching authenticated GitHub identity")
+
+	identity, err := m.adapter.GetWhoamiResource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity resource: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(identity, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource to JSON: %w", err)
+	}
+
+	contents := []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
+		},
+	}
+
 	return contents, nil
 }
 
@@ -187,6 +786,170 @@ func extractFullNameFromURI(uri string) string {
 	return fullName
 }
 
+// extractSubresourceFullName extracts owner/repo from
+// github://starred/{owner}/{repo}/{name}, e.g. the readme or releases of a repository
+func extractSubresourceFullName(uri, name string) string {
+	suffix := "/" + name
+	if !strings.HasSuffix(uri, suffix) {
+		return ""
+	}
+
+	fullName := extractFullNameFromURI(strings.TrimSuffix(uri, suffix))
+	if strings.Count(fullName, "/") != 1 {
+		return ""
+	}
+	return fullName
+}
+
+// extractListSlugFromURI extracts the slug from github://starred/lists/{slug}
+func extractListSlugFromURI(uri string) string {
+	const prefix = "github://starred/lists/"
+	if !strings.HasPrefix(uri, prefix) {
+		return ""
+	}
+
+	slug := strings.TrimPrefix(uri, prefix)
+	if slug == "" || strings.Contains(slug, "/") {
+		return ""
+	}
+	return slug
+}
+
+// parseContentURI splits github://starred/{owner}/{repo}/{kind}/{ref}[/{path}]
+// into owner/repo, ref and path, decoding percent-encoded characters. The ref
+// is a single segment, so refs containing slashes are written with %2F, as in
+// tree/feature%2Flogin. fullName is empty when uri does not match.
+func parseContentURI(uri, kind string) (fullName, ref, filePath string) {
+	const prefix = "github://starred/"
+	if !strings.HasPrefix(uri, prefix) {
+		return "", "", ""
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(uri, prefix), "/", 5)
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" || parts[2] != kind || parts[3] == "" {
+		return "", "", ""
+	}
+
+	ref, err := url.PathUnescape(parts[3])
+	if err != nil {
+		return "", "", ""
+	}
+	if len(parts) == 5 {
+		if filePath, err = url.PathUnescape(parts[4]); err != nil {
+			return "", "", ""
+		}
+	}
+
+	return parts[0] + "/" + parts[1], ref, filePath
+}
+
+// parsePageURI extracts the pagination cursor and page size from the query of a
+// listing URI such as github://starred?cursor={cursor}&limit={n}
+func parsePageURI(uri string, defaultLimit int) (string, int, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid URI format: %s", uri)
+	}
+
+	values := parsed.Query()
+	limit := defaultLimit
+	if rawLimit := values.Get("limit"); rawLimit != "" {
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil || limit < 1 {
+			return "", 0, fmt.Errorf("invalid limit %q: must be a positive number", rawLimit)
+		}
+	}
+	if limit > resource.MaxPageSize {
+		limit = resource.MaxPageSize
+	}
+
+	return values.Get("cursor"), limit, nil
+}
+
+// parseRepoFilterURI reads the repository metadata filters of a listing URI
+// such as github://starred?archived=false&topic={topic}, which take the values
+// of the matching search_starred arguments
+func parseRepoFilterURI(uri string) (resource.SearchOptions, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return resource.SearchOptions{}, fmt.Errorf("invalid URI format: %s", uri)
+	}
+
+	values := parsed.Query()
+	filter := resource.SearchOptions{
+		Topic:      values.Get("topic"),
+		License:    values.Get("license"),
+		Visibility: values.Get("visibility"),
+	}
+	for _, flag := range []struct {
+		key   string
+		value **bool
+	}{
+		{"archived", &filter.Archived},
+		{"fork", &filter.Fork},
+		{"template", &filter.Template},
+	} {
+		raw := values.Get(flag.key)
+		if raw == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return resource.SearchOptions{}, fmt.Errorf("invalid %s %q: must be true or false", flag.key, raw)
+		}
+		*flag.value = &b
+	}
+
+	return filter, nil
+}
+
+// parseSearchURI extracts the query and result limit from github://starred/search?q={query}&limit={n}
+func parseSearchURI(uri string) (string, int, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid URI format: %s", uri)
+	}
+
+	values := parsed.Query()
+	query := strings.TrimSpace(values.Get("q"))
+	if query == "" {
+		return "", 0, fmt.Errorf("missing search query: expected github://starred/search?q={query}")
+	}
+
+	limit := defaultToolLimit
+	if rawLimit := values.Get("limit"); rawLimit != "" {
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid limit %q: must be a number", rawLimit)
+		}
+	}
+
+	return query, clampLimit(limit), nil
+}
+
+// parseHistoryURI builds star history options from github://starred/history?since={date}&until={date}&limit={n}
+func parseHistoryURI(uri string) (resource.SearchOptions, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return resource.SearchOptions{}, fmt.Errorf("invalid URI format: %s", uri)
+	}
+
+	values := parsed.Query()
+	limit := defaultToolLimit
+	if rawLimit := values.Get("limit"); rawLimit != "" {
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil {
+			return resource.SearchOptions{}, fmt.Errorf("invalid limit %q: must be a number", rawLimit)
+		}
+	}
+
+	opts, err := starHistoryOptions(values.Get("since"), values.Get("until"), limit)
+	if err != nil {
+		return resource.SearchOptions{}, err
+	}
+	return opts, opts.Validate()
+}
+
 // extractUsernameFromURI extracts username from github://starred/users/{username}
 func extractUsernameFromURI(uri string) string {
 	// Expected format: github://starred/users/{username}
@@ -195,13 +958,102 @@ func extractUsernameFromURI(uri string) string {
 		return ""
 	}
 
-	// Extract the username after the prefix
-	username := uri[len(prefix):]
+	// Extract the username after the prefix, dropping any pagination query
+	username, _, _ := strings.Cut(uri[len(prefix):], "?")
 	return username
 }
 
-// Start starts the MCP server using stdio transport
+// Start starts the MCP server using the configured transport.
+// It blocks until the transport stops or ctx is cancelled.
 func (m *MCPServer) Start(ctx context.Context) error {
-	log.Println("Starting MCP server on stdio...")
-	return server.ServeStdio(m.server)
+	switch m.cfg.Transport {
+	case config.TransportStdio, "":
+		log.Println("Starting MCP server on stdio...")
+		err := server.NewStdioServer(m.server).Listen(ctx, os.Stdin, os.Stdout)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	case config.TransportSSE:
+		// The HTTP transports run on an http.Server of our own, which exists
+		// before Start and so can be shut down at any point, and which carries
+		// the tenant and authorization handlers the configuration enables
+		httpServer := &http.Server{}
+		sseServer := server.NewSSEServer(m.server,
+			// Relative message endpoints keep SSE working behind proxies and for 0.0.0.0 binds
+			server.WithUseFullURLForMessageEndpoint(false),
+			server.WithHTTPServer(httpServer),
+		)
+		handler, err := m.httpHandler(sseServer)
+		if err != nil {
+			return err
+		}
+		httpServer.Handler = handler
+		return m.serveHTTP(sseServer, "/sse")
+	case config.TransportStreamableHTTP:
+		httpServer := &http.Server{}
+		streamableServer := server.NewStreamableHTTPServer(m.server, server.WithStreamableHTTPServer(httpServer))
+
+		// A custom HTTP server needs its own routing to the endpoint
+		endpoint := http.NewServeMux()
+		endpoint.Handle("/mcp", streamableServer)
+		handler, err := m.httpHandler(endpoint)
+		if err != nil {
+			return err
+		}
+		httpServer.Handler = handler
+		return m.serveHTTP(streamableServer, "/mcp")
+	default:
+		return fmt.Errorf("unsupported transport: %s", m.cfg.Transport)
+	}
+}
+
+// httpHandler wraps an MCP endpoint with the tenant and authorization
+// handlers the configuration enables
+func (m *MCPServer) httpHandler(handler http.Handler) (http.Handler, error) {
+	if m.cfg.MultiTenant {
+		handler = m.tenantHandler(handler)
+	}
+	if !m.cfg.MCPAuthEnabled() {
+		return handler, nil
+	}
+	return m.authorizedHandler(handler)
+}
+
+// serveHTTP runs an HTTP-based transport on the configured address. It
+// returns at once when Shutdown was called before.
+func (m *MCPServer) serveHTTP(transport httpTransport, endpoint string) error {
+	m.mu.Lock()
+	if m.stopping {
+		m.mu.Unlock()
+		return nil
+	}
+	m.transport = transport
+	m.mu.Unlock()
+
+	addr := m.cfg.Address()
+	log.Printf("Starting MCP server (%s) on http://%s%s", m.cfg.Transport, addr, endpoint)
+	if !m.cfg.MCPAuthEnabled() && !m.cfg.MultiTenant {
+		log.Printf("Client authorization is disabled: anyone who can reach %s can use the server's GitHub credentials", addr)
+	}
+
+	if err := transport.Start(addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown gracefully stops an HTTP-based transport, or keeps it from
+// starting when Start has not got that far yet.
+// The stdio transport is stopped by cancelling the context passed to Start.
+func (m *MCPServer) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.stopping = true
+	transport := m.transport
+	m.mu.Unlock()
+
+	if transport == nil {
+		return nil
+	}
+	return transport.Shutdown(ctx)
 }
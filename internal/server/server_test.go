@@ -1,7 +1,13 @@
 package server
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/timduly4/mcp-server/internal/config"
+	"github.com/timduly4/mcp-server/internal/resource"
 )
 
 // TestExtractFullNameFromURI tests URI parsing for {owner}/{repo} pattern
@@ -48,6 +54,167 @@ func TestExtractFullNameFromURI(t *testing.T) {
 	}
 }
 
+// TestExtractSubresourceFullName tests URI parsing for the {owner}/{repo}/{name} patterns
+func TestExtractSubresourceFullName(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		resource string
+		expected string
+	}{
+		{
+			name:     "readme",
+			uri:      "github://starred/mark3labs/mcp-go/readme",
+			resource: "readme",
+			expected: "mark3labs/mcp-go",
+		},
+		{
+			name:     "releases",
+			uri:      "github://starred/mark3labs/mcp-go/releases",
+			resource: "releases",
+			expected: "mark3labs/mcp-go",
+		},
+		{
+			name:     "different subresource",
+			uri:      "github://starred/mark3labs/mcp-go/releases",
+			resource: "readme",
+			expected: "",
+		},
+		{
+			name:     "repository URI without subresource",
+			uri:      "github://starred/mark3labs/mcp-go",
+			resource: "readme",
+			expected: "",
+		},
+		{
+			name:     "missing repository",
+			uri:      "github://starred/mark3labs/readme",
+			resource: "readme",
+			expected: "",
+		},
+		{
+			name:     "wrong prefix",
+			uri:      "github://repos/mark3labs/mcp-go/readme",
+			resource: "readme",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := extractSubresourceFullName(tt.uri, tt.resource)
+			if result != tt.expected {
+				t.Errorf("extractSubresourceFullName(%q, %q) = %q, want %q", tt.uri, tt.resource, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestExtractListSlugFromURI tests URI parsing for the lists/{slug} pattern
+func TestExtractListSlugFromURI(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		expected string
+	}{
+		{
+			name:     "valid URI",
+			uri:      "github://starred/lists/go-tools",
+			expected: "go-tools",
+		},
+		{
+			name:     "list index",
+			uri:      "github://starred/lists",
+			expected: "",
+		},
+		{
+			name:     "empty slug",
+			uri:      "github://starred/lists/",
+			expected: "",
+		},
+		{
+			name:     "nested path",
+			uri:      "github://starred/lists/go-tools/readme",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := extractListSlugFromURI(tt.uri)
+			if result != tt.expected {
+				t.Errorf("extractListSlugFromURI(%q) = %q, want %q", tt.uri, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestParseContentURI tests URI parsing for the tree and blob patterns
+func TestParseContentURI(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		kind     string
+		fullName string
+		ref      string
+		path     string
+	}{
+		{
+			name:     "tree",
+			uri:      "github://starred/mark3labs/mcp-go/tree/main",
+			kind:     "tree",
+			fullName: "mark3labs/mcp-go",
+			ref:      "main",
+		},
+		{
+			name:     "blob with nested path",
+			uri:      "github://starred/mark3labs/mcp-go/blob/v0.58.0/server/server.go",
+			kind:     "blob",
+			fullName: "mark3labs/mcp-go",
+			ref:      "v0.58.0",
+			path:     "server/server.go",
+		},
+		{
+			name:     "ref with slash and escaped path",
+			uri:      "github://starred/mark3labs/mcp-go/blob/feature%2Flogin/docs/getting%20started%3F.md",
+			kind:     "blob",
+			fullName: "mark3labs/mcp-go",
+			ref:      "feature/login",
+			path:     "docs/getting started?.md",
+		},
+		{
+			name: "invalid escape",
+			uri:  "github://starred/mark3labs/mcp-go/tree/main%zz",
+			kind: "tree",
+		},
+		{
+			name: "wrong kind",
+			uri:  "github://starred/mark3labs/mcp-go/tree/main",
+			kind: "blob",
+		},
+		{
+			name: "missing ref",
+			uri:  "github://starred/mark3labs/mcp-go/tree/",
+			kind: "tree",
+		},
+		{
+			name: "repository URI",
+			uri:  "github://starred/mark3labs/mcp-go",
+			kind: "tree",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fullName, ref, path := parseContentURI(tt.uri, tt.kind)
+			if fullName != tt.fullName || ref != tt.ref || path != tt.path {
+				t.Errorf("parseContentURI(%q, %q) = (%q, %q, %q), want (%q, %q, %q)",
+					tt.uri, tt.kind, fullName, ref, path, tt.fullName, tt.ref, tt.path)
+			}
+		})
+	}
+}
+
 // TestExtractUsernameFromURI tests URI parsing for users/{username} pattern
 func TestExtractUsernameFromURI(t *testing.T) {
 	tests := []struct {
@@ -85,6 +252,11 @@ func TestExtractUsernameFromURI(t *testing.T) {
 			uri:      "github://starred/timduly4",
 			expected: "",
 		},
+		{
+			name:     "pagination query",
+			uri:      "github://starred/users/octocat?cursor=abc&limit=10",
+			expected: "octocat",
+		},
 	}
 
 	for _, tt := range tests {
@@ -121,6 +293,335 @@ func TestURIRouting_ConflictPrevention(t *testing.T) {
 		t.Errorf("extractUsernameFromURI should extract 'timduly4', got %q", usernameResult)
 	}
 
-	t.Log("✓ Routing conflict documented: users/{username} pattern must be registered BEFORE {owner}/{repo}")
-	t.Log("✓ Correct routing order prevents 'repository users/timduly4 not found' error")
+	// mcp-go does not match templates in registration order, so
+	// handleGetStarredRepo hands users/{username} URIs to handleListUserStarred
+	t.Log("✓ Routing conflict documented: {owner}/{repo} delegates users/{username} URIs")
+}
+
+// TestParsePageURI tests cursor and page size extraction from listing URIs
+func TestParsePageURI(t *testing.T) {
+	tests := []struct {
+		name       string
+		uri        string
+		wantCursor string
+		wantLimit  int
+		wantErr    bool
+	}{
+		{
+			name:      "no query uses default page size",
+			uri:       "github://starred",
+			wantLimit: 50,
+		},
+		{
+			name:       "cursor and limit",
+			uri:        "github://starred?cursor=eyJwIjoyfQ&limit=10",
+			wantCursor: "eyJwIjoyfQ",
+			wantLimit:  10,
+		},
+		{
+			name:      "limit clamped to maximum",
+			uri:       "github://starred/users/octocat?limit=1000",
+			wantLimit: resource.MaxPageSize,
+		},
+		{
+			name:    "invalid limit",
+			uri:     "github://starred?limit=0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, limit, err := parsePageURI(tt.uri, 50)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePageURI(%q) error = %v, wantErr %v", tt.uri, err, tt.wantErr)
+			}
+			if cursor != tt.wantCursor || limit != tt.wantLimit {
+				t.Errorf("parsePageURI(%q) = %q, %d, want %q, %d", tt.uri, cursor, limit, tt.wantCursor, tt.wantLimit)
+			}
+		})
+	}
+}
+
+// TestParseRepoFilterURI tests repository filter extraction from listing URIs
+func TestParseRepoFilterURI(t *testing.T) {
+	filter, err := parseRepoFilterURI("github://starred?cursor=eyJwIjoyfQ&topic=cli&license=MIT&archived=false&fork=true")
+	if err != nil {
+		t.Fatalf("parseRepoFilterURI() error = %v", err)
+	}
+
+	if filter.Topic != "cli" || filter.License != "MIT" || filter.Visibility != "" {
+		t.Errorf("filter = %+v, want topic cli and license MIT", filter)
+	}
+	if filter.Archived == nil || *filter.Archived {
+		t.Errorf("Archived = %v, want false", filter.Archived)
+	}
+	if filter.Fork == nil || !*filter.Fork {
+		t.Errorf("Fork = %v, want true", filter.Fork)
+	}
+	if filter.Template != nil {
+		t.Errorf("Template = %v, want nil when omitted", *filter.Template)
+	}
+
+	if _, err := parseRepoFilterURI("github://starred/users/octocat?archived=maybe"); err == nil {
+		t.Error("parseRepoFilterURI() expected error for a non-boolean flag, got nil")
+	}
+}
+
+// TestParseSearchURI tests query extraction from github://starred/search?q={query}
+func TestParseSearchURI(t *testing.T) {
+	tests := []struct {
+		name      string
+		uri       string
+		wantQuery string
+		wantLimit int
+		wantErr   bool
+	}{
+		{
+			name:      "query only",
+			uri:       "github://starred/search?q=rust",
+			wantQuery: "rust",
+			wantLimit: defaultToolLimit,
+		},
+		{
+			name:      "escaped query with limit",
+			uri:       "github://starred/search?q=web%20framework&limit=5",
+			wantQuery: "web framework",
+			wantLimit: 5,
+		},
+		{
+			name:    "missing query",
+			uri:     "github://starred/search",
+			wantErr: true,
+		},
+		{
+			name:    "invalid limit",
+			uri:     "github://starred/search?q=rust&limit=many",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, limit, err := parseSearchURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSearchURI(%q) error = %v, wantErr %v", tt.uri, err, tt.wantErr)
+			}
+			if query != tt.wantQuery || limit != tt.wantLimit {
+				t.Errorf("parseSearchURI(%q) = %q, %d, want %q, %d", tt.uri, query, limit, tt.wantQuery, tt.wantLimit)
+			}
+		})
+	}
+}
+
+// TestParseHistoryURI tests date range extraction from github://starred/history
+func TestParseHistoryURI(t *testing.T) {
+	tests := []struct {
+		name      string
+		uri       string
+		wantSince time.Time
+		wantUntil time.Time
+		wantLimit int
+		wantErr   bool
+	}{
+		{
+			name:      "no bounds",
+			uri:       "github://starred/history",
+			wantLimit: defaultToolLimit,
+		},
+		{
+			name:      "date range covers the whole end day",
+			uri:       "github://starred/history?since=2024-09-01&until=2024-09-30&limit=10",
+			wantSince: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+			wantUntil: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+			wantLimit: 10,
+		},
+		{
+			name:      "RFC 3339 bound is exact",
+			uri:       "github://starred/history?until=2024-09-30T12:00:00Z",
+			wantUntil: time.Date(2024, 9, 30, 12, 0, 0, 0, time.UTC),
+			wantLimit: defaultToolLimit,
+		},
+		{
+			name:    "invalid date",
+			uri:     "github://starred/history?since=last-month",
+			wantErr: true,
+		},
+		{
+			name:    "reversed range",
+			uri:     "github://starred/history?since=2024-10-01&until=2024-09-01",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseHistoryURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHistoryURI(%q) error = %v, wantErr %v", tt.uri, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !opts.StarredSince.Equal(tt.wantSince) || !opts.StarredUntil.Equal(tt.wantUntil) {
+				t.Errorf("range = %v..%v, want %v..%v", opts.StarredSince, opts.StarredUntil, tt.wantSince, tt.wantUntil)
+			}
+			if opts.Sort != resource.SortByStarred || opts.Limit != tt.wantLimit {
+				t.Errorf("opts = %+v, want sort %s and limit %d", opts, resource.SortByStarred, tt.wantLimit)
+			}
+		})
+	}
+}
+
+// TestStart_UnsupportedTransport tests that an unknown transport mode is rejected
+func TestStart_UnsupportedTransport(t *testing.T) {
+	srv := NewMCPServer(&config.Config{Transport: "carrier-pigeon"}, nil)
+
+	if err := srv.Start(context.Background()); err == nil {
+		t.Error("Start() expected error for unsupported transport, got nil")
+	}
+}
+
+// TestShutdown_HTTPTransports tests that HTTP-based transports stop cleanly
+// on Shutdown, however far Start has got when it is called
+func TestShutdown_HTTPTransports(t *testing.T) {
+	for _, transport := range []string{config.TransportSSE, config.TransportStreamableHTTP} {
+		for _, shutdownFirst := range []bool{false, true} {
+			name := transport
+			if shutdownFirst {
+				name += " shut down before start"
+			}
+			t.Run(name, func(t *testing.T) {
+				srv := NewMCPServer(&config.Config{
+					Transport:  transport,
+					ServerHost: "127.0.0.1",
+					ServerPort: "0",
+				}, nil)
+
+				if shutdownFirst {
+					if err := srv.Shutdown(context.Background()); err != nil {
+						t.Fatalf("Shutdown() error = %v", err)
+					}
+				}
+
+				errCh := make(chan error, 1)
+				go func() {
+					errCh <- srv.Start(context.Background())
+				}()
+
+				if !shutdownFirst {
+					if err := srv.Shutdown(context.Background()); err != nil {
+						t.Fatalf("Shutdown() error = %v", err)
+					}
+				}
+
+				select {
+				case err := <-errCh:
+					if err != nil {
+						t.Errorf("Start() error = %v, want nil after Shutdown", err)
+					}
+				case <-time.After(2 * time.Second):
+					t.Fatal("Start() did not return after Shutdown")
+				}
+			})
+		}
+	}
+}
+
+// TestRequestTimeout tests that resource handlers receive a context bounded by the configured timeout
+func TestRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantDeadline bool
+	}{
+		{
+			name:         "timeout configured",
+			timeout:      time.Minute,
+			wantDeadline: true,
+		},
+		{
+			name:         "timeout disabled",
+			timeout:      0,
+			wantDeadline: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hasDeadline bool
+			handler := requestTimeout(tt.timeout)(func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				_, hasDeadline = ctx.Deadline()
+				return nil, nil
+			})
+
+			if _, err := handler(context.Background(), mcp.ReadResourceRequest{}); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if hasDeadline != tt.wantDeadline {
+				t.Errorf("context has deadline = %v, want %v", hasDeadline, tt.wantDeadline)
+			}
+		})
+	}
+}
+
+func TestCanonicalURI(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		uriBase  string
+		expected string
+	}{
+		{
+			name:     "github.com",
+			uri:      "github://starred/owner/repo",
+			uriBase:  "github://",
+			expected: "github://starred/owner/repo",
+		},
+		{
+			name:     "enterprise server",
+			uri:      "github://ghe.example.com/starred/owner/repo/readme",
+			uriBase:  "github://ghe.example.com/",
+			expected: "github://starred/owner/repo/readme",
+		},
+		{
+			name:     "other host left alone",
+			uri:      "github://other.example.com/starred/owner/repo",
+			uriBase:  "github://ghe.example.com/",
+			expected: "github://other.example.com/starred/owner/repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := canonicalURI(tt.uri, tt.uriBase); result != tt.expected {
+				t.Errorf("canonicalURI(%q, %q) = %q, want %q", tt.uri, tt.uriBase, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestHostURIs tests that handlers see github.com URIs and clients get back the URI they asked for
+func TestHostURIs(t *testing.T) {
+	var handled string
+	handler := hostURIs("github://ghe.example.com/")(func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		handled = request.Params.URI
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: request.Params.URI, MIMEType: "application/json", Text: "{}"},
+		}, nil
+	})
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "github://ghe.example.com/starred/owner/repo"
+
+	contents, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	if handled != "github://starred/owner/repo" {
+		t.Errorf("handler URI = %q, want github://starred/owner/repo", handled)
+	}
+	if uri := contents[0].(mcp.TextResourceContents).URI; uri != request.Params.URI {
+		t.Errorf("contents URI = %q, want %q", uri, request.Params.URI)
+	}
 }
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// subscriptions tracks the resource URIs each client session has subscribed to
+type subscriptions struct {
+	mu        sync.Mutex
+	bySession map[string]map[string]bool
+
+	// uriBase prefixes the server's resource URIs, see resource.URIBase
+	uriBase string
+}
+
+func newSubscriptions(uriBase string) *subscriptions {
+	return &subscriptions{
+		bySession: make(map[string]map[string]bool),
+		uriBase:   uriBase,
+	}
+}
+
+// hooks records subscriptions as clients subscribe, unsubscribe and disconnect
+func (s *subscriptions) hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+
+	hooks.AddAfterSubscribe(func(ctx context.Context, id any, message *mcp.SubscribeRequest, result *mcp.EmptyResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			s.add(session.SessionID(), message.Params.URI)
+		}
+	})
+	hooks.AddAfterUnsubscribe(func(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			s.remove(session.SessionID(), message.Params.URI)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		s.removeSession(session.SessionID())
+	})
+
+	return hooks
+}
+
+func (s *subscriptions) add(sessionID, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bySession[sessionID] == nil {
+		s.bySession[sessionID] = make(map[string]bool)
+	}
+	s.bySession[sessionID][uri] = true
+}
+
+func (s *subscriptions) remove(sessionID, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.bySession[sessionID], uri)
+	if len(s.bySession[sessionID]) == 0 {
+		delete(s.bySession, sessionID)
+	}
+}
+
+func (s *subscriptions) removeSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.bySession, sessionID)
+}
+
+// matching returns, per session, the subscribed URIs that refer to one of
+// bases once their query (cursor, limit, ...) is stripped
+func (s *subscriptions) matching(bases ...string) map[string][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matches := make(map[string][]string)
+	for sessionID, uris := range s.bySession {
+		for uri := range uris {
+			base, _, _ := strings.Cut(uri, "?")
+			for _, b := range bases {
+				if base == b {
+					matches[sessionID] = append(matches[sessionID], uri)
+					break
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// usernames returns the users whose star lists have subscribers
+func (s *subscriptions) usernames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, uris := range s.bySession {
+		for uri := range uris {
+			if username := extractUsernameFromURI(canonicalURI(uri, s.uriBase)); username != "" {
+				seen[username] = true
+			}
+		}
+	}
+
+	usernames := make([]string, 0, len(seen))
+	for username := range seen {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	return usernames
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/timduly4/mcp-server/internal/config"
+	"github.com/timduly4/mcp-server/internal/resource"
+)
+
+// fakeSession is a client session that records the notifications it receives
+type fakeSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *fakeSession) Initialize()       {}
+func (s *fakeSession) Initialized() bool { return true }
+func (s *fakeSession) SessionID() string { return s.id }
+func (s *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+// TestSubscriptions_Matching tests that query variants of a URI match its base
+func TestSubscriptions_Matching(t *testing.T) {
+	subs := newSubscriptions(resource.DefaultURIBase)
+	subs.add("a", "github://starred?limit=10")
+	subs.add("a", "github://starred/mark3labs/mcp-go")
+	subs.add("b", "github://starred/users/octocat")
+	subs.add("b", "github://starred/users/octocat?cursor=abc")
+
+	matches := subs.matching("github://starred")
+	if !reflect.DeepEqual(matches, map[string][]string{"a": {"github://starred?limit=10"}}) {
+		t.Errorf("matching(github://starred) = %v", matches)
+	}
+
+	matches = subs.matching("github://starred/users/octocat")
+	uris := matches["b"]
+	sort.Strings(uris)
+	if len(matches) != 1 || !reflect.DeepEqual(uris, []string{"github://starred/users/octocat", "github://starred/users/octocat?cursor=abc"}) {
+		t.Errorf("matching(users/octocat) = %v", matches)
+	}
+
+	if usernames := subs.usernames(); !reflect.DeepEqual(usernames, []string{"octocat"}) {
+		t.Errorf("usernames() = %v, want [octocat]", usernames)
+	}
+
+	subs.remove("a", "github://starred?limit=10")
+	subs.removeSession("b")
+	if matches := subs.matching("github://starred", "github://starred/users/octocat"); len(matches) != 0 {
+		t.Errorf("matching() after removal = %v, want none", matches)
+	}
+}
+
+// TestSubscribe_NotifiesSession tests the resources/subscribe round trip and
+// delivery of notifications/resources/updated to the subscribed session
+func TestSubscribe_NotifiesSession(t *testing.T) {
+	srv := NewMCPServer(&config.Config{}, nil)
+
+	session := &fakeSession{id: "session-1", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := srv.server.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession() error = %v", err)
+	}
+	ctx := srv.server.WithContext(context.Background(), session)
+
+	response := srv.server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"github://starred"}}`))
+	if _, ok := response.(mcp.JSONRPCResponse); !ok {
+		t.Fatalf("resources/subscribe response = %#v, want success", response)
+	}
+
+	srv.notifyUpdated("github://starred/mark3labs/mcp-go")
+	srv.notifyUpdated(srv.starredListURIs()...)
+
+	select {
+	case notification := <-session.notifications:
+		if notification.Method != mcp.MethodNotificationResourceUpdated {
+			t.Errorf("Method = %s, want %s", notification.Method, mcp.MethodNotificationResourceUpdated)
+		}
+		if uri := notification.Params.AdditionalFields["uri"]; uri != "github://starred" {
+			t.Errorf("uri = %v, want github://starred", uri)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no notification delivered to the subscribed session")
+	}
+
+	select {
+	case notification := <-session.notifications:
+		t.Errorf("unexpected notification %+v", notification)
+	default:
+	}
+
+	srv.server.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/unsubscribe","params":{"uri":"github://starred"}}`))
+	if matches := srv.subs.matching(srv.starredListURIs()...); len(matches) != 0 {
+		t.Errorf("subscriptions after unsubscribe = %v, want none", matches)
+	}
+}
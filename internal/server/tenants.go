@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/github"
+	"github.com/timduly4/mcp-server/internal/index"
+	"github.com/timduly4/mcp-server/internal/resource"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// GitHubTokenHeader carries a client's GitHub token in multi-tenant mode
+	GitHubTokenHeader = "X-GitHub-Token"
+
+	// tenantIdleTTL is how long the client of a GitHub token is kept after its last request
+	tenantIdleTTL = 30 * time.Minute
+
+	// maxTenants caps how many GitHub tokens have a client at the same time;
+	// beyond it the least recently used tenant is dropped
+	maxTenants = 500
+
+	// tenantBuildTimeout bounds building a tenant, which checks its token with GitHub
+	tenantBuildTimeout = 30 * time.Second
+)
+
+// Tenant is the GitHub client and search index serving one GitHub token
+type Tenant struct {
+	Client *github.Client
+
+	// SearchIndex may be nil
+	SearchIndex *index.Index
+
+	// Release frees what the tenant holds, such as its response cache, once
+	// it is dropped. May be nil.
+	Release func()
+}
+
+// TenantFunc builds the tenant serving one GitHub token. id identifies the
+// token without revealing it, for example to name its cache directory. It
+// fails with github.ErrBadCredentials when GitHub rejects the token.
+type TenantFunc func(ctx context.Context, id, token string) (*Tenant, error)
+
+// Option configures optional MCPServer behaviour
+type Option func(*MCPServer)
+
+// WithTenants builds the GitHub client of each token clients present in
+// multi-tenant mode
+func WithTenants(build TenantFunc) Option {
+	return func(m *MCPServer) {
+		m.tenants = newTenants(build)
+	}
+}
+
+// tenant is a Tenant in use
+type tenant struct {
+	*Tenant
+	lastUsed time.Time
+}
+
+// tenants caches a tenant per GitHub token so the requests of a session
+// share one client and its response cache
+type tenants struct {
+	build TenantFunc
+	now   func() time.Time
+
+	// building makes concurrent first requests of a token build one tenant
+	building singleflight.Group
+
+	mu   sync.Mutex
+	byID map[string]*tenant
+}
+
+func newTenants(build TenantFunc) *tenants {
+	return &tenants{
+		build: build,
+		now:   time.Now,
+		byID:  make(map[string]*tenant),
+	}
+}
+
+// get returns the tenant of token, building it on first use. Tenants idle for
+// longer than tenantIdleTTL are dropped.
+func (t *tenants) get(ctx context.Context, token string) (*tenant, error) {
+	sum := sha256.Sum256([]byte(token))
+	id := hex.EncodeToString(sum[:])
+
+	if existing := t.lookup(id); existing != nil {
+		return existing, nil
+	}
+
+	built, err, _ := t.building.Do(id, func() (any, error) {
+		if existing := t.lookup(id); existing != nil {
+			return existing, nil
+		}
+
+		// The build serves every request waiting for it, so the first one
+		// going away must not cancel it
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tenantBuildTimeout)
+		defer cancel()
+
+		created, err := t.build(ctx, id, token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		return t.add(id, created), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return built.(*tenant), nil
+}
+
+// lookup returns the tenant of id, or nil when there is none, and drops idle tenants
+func (t *tenants) lookup(id string) *tenant {
+	t.mu.Lock()
+	now := t.now()
+	var dropped []*tenant
+	for key, idle := range t.byID {
+		if now.Sub(idle.lastUsed) > tenantIdleTTL {
+			dropped = append(dropped, idle)
+			delete(t.byID, key)
+		}
+	}
+
+	existing := t.byID[id]
+	if existing != nil {
+		existing.lastUsed = now
+	}
+	t.mu.Unlock()
+
+	release(dropped)
+	return existing
+}
+
+// add stores a new tenant, dropping the least recently used one when there
+// are maxTenants already
+func (t *tenants) add(id string, created *Tenant) *tenant {
+	t.mu.Lock()
+	var dropped []*tenant
+	for len(t.byID) >= maxTenants {
+		oldestID := ""
+		for key, candidate := range t.byID {
+			if oldestID == "" || candidate.lastUsed.Before(t.byID[oldestID].lastUsed) {
+				oldestID = key
+			}
+		}
+		dropped = append(dropped, t.byID[oldestID])
+		delete(t.byID, oldestID)
+	}
+
+	added := &tenant{Tenant: created, lastUsed: t.now()}
+	t.byID[id] = added
+	t.mu.Unlock()
+
+	release(dropped)
+	return added
+}
+
+// release frees the resources of dropped tenants
+func release(dropped []*tenant) {
+	for _, d := range dropped {
+		if d.Release != nil {
+			d.Release()
+		}
+	}
+}
+
+// tenantHandler serves each request with the GitHub client of the token the
+// client sent, in the X-GitHub-Token header or, when client authorization is
+// disabled, as the bearer token. Requests without a token are rejected.
+func (m *MCPServer) tenantHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := githubToken(r, !m.cfg.MCPAuthEnabled())
+		if token == "" {
+			http.Error(w, "a GitHub token is required in the "+GitHubTokenHeader+" header", http.StatusUnauthorized)
+			return
+		}
+		if m.tenants == nil {
+			http.Error(w, "multi-tenant mode is not configured", http.StatusInternalServerError)
+			return
+		}
+
+		t, err := m.tenants.get(r.Context(), token)
+		if errors.Is(err, github.ErrBadCredentials) {
+			http.Error(w, "GitHub rejected the token: it is invalid, expired or revoked", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to serve tenant: %v", err)
+			http.Error(w, "failed to create GitHub client", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := resource.WithIdentity(r.Context(), t.Client, t.SearchIndex)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// githubToken returns the GitHub token of a request, taking the
+// Authorization header into account only when bearer is set
+func githubToken(r *http.Request, bearer bool) string {
+	if token := strings.TrimSpace(r.Header.Get(GitHubTokenHeader)); token != "" {
+		return token
+	}
+	if !bearer {
+		return ""
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || (!strings.EqualFold(scheme, "Bearer") && !strings.EqualFold(scheme, "token")) {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/config"
+	"github.com/timduly4/mcp-server/internal/github"
+)
+
+// TestTenants tests that a token's client is reused until it idles out
+func TestTenants(t *testing.T) {
+	built := map[string]int{}
+	released := map[string]int{}
+	pool := newTenants(func(ctx context.Context, id, token string) (*Tenant, error) {
+		built[token]++
+		if len(id) != 64 || id == token {
+			t.Errorf("id = %q, want the hex SHA-256 of the token", id)
+		}
+		return &Tenant{Client: &github.Client{}, Release: func() { released[token]++ }}, nil
+	})
+	ctx := context.Background()
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	first, err := pool.get(ctx, "token-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := pool.get(ctx, "token-a")
+	if first != again {
+		t.Error("expected the same tenant for the same token")
+	}
+	other, _ := pool.get(ctx, "token-b")
+	if other == first {
+		t.Error("expected a separate tenant for another token")
+	}
+
+	now = now.Add(tenantIdleTTL + time.Minute)
+	if _, err := pool.get(ctx, "token-a"); err != nil {
+		t.Fatal(err)
+	}
+	if built["token-a"] != 2 || built["token-b"] != 1 {
+		t.Errorf("built = %v, want token-a twice and token-b once", built)
+	}
+	if len(pool.byID) != 1 {
+		t.Errorf("expected idle tenants to be dropped, have %d", len(pool.byID))
+	}
+	if released["token-a"] != 1 || released["token-b"] != 1 {
+		t.Errorf("released = %v, want each dropped tenant released once", released)
+	}
+}
+
+// TestTenants_Cap tests that the least recently used tenant is dropped once
+// maxTenants tokens have a client
+func TestTenants_Cap(t *testing.T) {
+	var released []string
+	pool := newTenants(func(ctx context.Context, id, token string) (*Tenant, error) {
+		return &Tenant{Client: &github.Client{}, Release: func() { released = append(released, token) }}, nil
+	})
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < maxTenants; i++ {
+		now = now.Add(time.Second)
+		if _, err := pool.get(ctx, fmt.Sprintf("token-%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// token-0 is used again, so token-1 is now the least recently used
+	now = now.Add(time.Second)
+	pool.get(ctx, "token-0")
+	now = now.Add(time.Second)
+	pool.get(ctx, "token-new")
+
+	if len(pool.byID) != maxTenants {
+		t.Errorf("have %d tenants, want %d", len(pool.byID), maxTenants)
+	}
+	if len(released) != 1 || released[0] != "token-1" {
+		t.Errorf("released = %v, want [token-1]", released)
+	}
+}
+
+// TestTenants_FirstRequestCancelled tests that a request sharing the build
+// of a tenant is not failed by the request that started it going away
+func TestTenants_FirstRequestCancelled(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	pool := newTenants(func(ctx context.Context, id, token string) (*Tenant, error) {
+		close(started)
+		<-finish
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("tenant build has no deadline")
+		}
+		return &Tenant{Client: &github.Client{}}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := pool.get(ctx, "token")
+		first <- err
+	}()
+	<-started
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := pool.get(context.Background(), "token")
+		second <- err
+	}()
+
+	cancel()
+	close(finish)
+	if err := <-first; err != nil {
+		t.Errorf("first get() error = %v", err)
+	}
+	if err := <-second; err != nil {
+		t.Errorf("second get() error = %v", err)
+	}
+}
+
+// TestTenantHandler tests which requests reach the MCP endpoint in multi-tenant mode
+func TestTenantHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		auth      bool
+		header    map[string]string
+		buildErr  error
+		wantCode  int
+		wantToken string
+	}{
+		{name: "no token", wantCode: http.StatusUnauthorized},
+		{name: "token header", header: map[string]string{GitHubTokenHeader: "ghp_a"}, wantCode: http.StatusOK, wantToken: "ghp_a"},
+		{name: "bearer token", header: map[string]string{"Authorization": "Bearer ghp_b"}, wantCode: http.StatusOK, wantToken: "ghp_b"},
+		{name: "bearer token is for the server when authorization is enabled", auth: true, header: map[string]string{"Authorization": "Bearer jwt"}, wantCode: http.StatusUnauthorized},
+		{name: "token header with authorization", auth: true, header: map[string]string{"Authorization": "Bearer jwt", GitHubTokenHeader: "ghp_c"}, wantCode: http.StatusOK, wantToken: "ghp_c"},
+		{name: "client creation fails", header: map[string]string{GitHubTokenHeader: "ghp_d"}, buildErr: errors.New("boom"), wantCode: http.StatusInternalServerError, wantToken: "ghp_d"},
+		{name: "token rejected by GitHub", header: map[string]string{GitHubTokenHeader: "ghp_e"}, buildErr: github.ErrBadCredentials, wantCode: http.StatusUnauthorized, wantToken: "ghp_e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Transport: config.TransportStreamableHTTP, MultiTenant: true}
+			if tt.auth {
+				cfg.MCPAuthResource = "https://mcp.example.com/mcp"
+				cfg.MCPAuthIssuer = "https://auth.example.com"
+			}
+
+			var gotToken string
+			srv := NewMCPServer(cfg, nil, WithTenants(func(ctx context.Context, id, token string) (*Tenant, error) {
+				gotToken = token
+				if tt.buildErr != nil {
+					return nil, tt.buildErr
+				}
+				return &Tenant{Client: &github.Client{}}, nil
+			}))
+
+			handler := srv.tenantHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if gotToken != tt.wantToken {
+				t.Errorf("token = %q, want %q", gotToken, tt.wantToken)
+			}
+		})
+	}
+}
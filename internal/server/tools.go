@@ -0,0 +1,543 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/timduly4/mcp-server/internal/github"
+	"github.com/timduly4/mcp-server/internal/resource"
+)
+
+// Result limits for list-style tools
+const (
+	defaultToolLimit = 25
+	maxToolLimit     = 100
+)
+
+// registerTools sets up all MCP tools
+func (m *MCPServer) registerTools() {
+	searchStarredTool := mcp.NewTool(
+		"search_starred",
+		mcp.WithDescription("Search the authenticated user's starred repositories with filters and sorting. "+
+			"Returns only the matching repositories so the full star list never has to be loaded into context."),
+		mcp.WithTitleAnnotation("Search Starred Repositories"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Description("Case-insensitive text matched against the repository name and description"),
+		),
+		mcp.WithString("language",
+			mcp.Description("Primary language, e.g. Go or TypeScript"),
+		),
+		mcp.WithNumber("min_stars",
+			mcp.Description("Only include repositories with at least this many stars"),
+			mcp.Min(0),
+		),
+		mcp.WithString("owner",
+			mcp.Description("Only include repositories owned by this user or organization"),
+		),
+		mcp.WithString("updated_since",
+			mcp.Description("Only include repositories updated on or after this date (YYYY-MM-DD or RFC 3339)"),
+		),
+		mcp.WithString("starred_since",
+			mcp.Description("Only include repositories starred on or after this date (YYYY-MM-DD or RFC 3339)"),
+		),
+		mcp.WithString("starred_until",
+			mcp.Description("Only include repositories starred on or before this date (YYYY-MM-DD or RFC 3339)"),
+		),
+		mcp.WithString("sort",
+			mcp.Description("Sort field; defaults to the order in which repositories were starred"),
+			mcp.Enum(resource.SortByStars, resource.SortByForks, resource.SortByUpdated, resource.SortByPushed,
+				resource.SortByName, resource.SortByStarred),
+		),
+		mcp.WithString("order",
+			mcp.Description("Sort direction; defaults to asc for name and desc otherwise"),
+			mcp.Enum(resource.OrderAsc, resource.OrderDesc),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results (default %d, max %d)", defaultToolLimit, maxToolLimit)),
+			mcp.Min(1),
+			mcp.Max(maxToolLimit),
+		),
+	)
+	for _, opt := range repoFilterOptions() {
+		opt(&searchStarredTool)
+	}
+
+	m.server.AddTool(searchStarredTool, m.handleSearchStarred)
+
+	rankStarredTool := mcp.NewTool(
+		"search_starred_ranked",
+		mcp.WithDescription("Full-text search over the authenticated user's starred repositories, ranked by relevance (BM25). "+
+			"Matches repository names, descriptions, topics and, when enabled, README text. "+
+			"Use this for fuzzy, multi-word questions such as \"rust web framework\"."),
+		mcp.WithTitleAnnotation("Ranked Search of Starred Repositories"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Free-text search query"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results (default %d, max %d)", defaultToolLimit, maxToolLimit)),
+			mcp.Min(1),
+			mcp.Max(maxToolLimit),
+		),
+	)
+
+	m.server.AddTool(rankStarredTool, m.handleRankStarred)
+
+	starHistoryTool := mcp.NewTool(
+		"star_history",
+		mcp.WithDescription("List the repositories the authenticated user starred within a date range, newest star first. "+
+			"Use this for questions such as \"what did I star last month\"."),
+		mcp.WithTitleAnnotation("Star History"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("since",
+			mcp.Description("Only include stars on or after this date (YYYY-MM-DD or RFC 3339)"),
+		),
+		mcp.WithString("until",
+			mcp.Description("Only include stars on or before this date (YYYY-MM-DD or RFC 3339); a date includes the whole day"),
+		),
+		mcp.WithString("order",
+			mcp.Description("Sort direction by star time; defaults to desc (newest first)"),
+			mcp.Enum(resource.OrderAsc, resource.OrderDesc),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of results (default %d, max %d)", defaultToolLimit, maxToolLimit)),
+			mcp.Min(1),
+			mcp.Max(maxToolLimit),
+		),
+	)
+	for _, opt := range repoFilterOptions() {
+		opt(&starHistoryTool)
+	}
+
+	m.server.AddTool(starHistoryTool, m.handleStarHistory)
+
+	releasesTool := mcp.NewTool(
+		"starred_releases",
+		mcp.WithDescription("List the authenticated user's starred repositories that published a release since a date, "+
+			"most recent release first. Use this to watch starred dependencies for new versions. "+
+			"Each starred repository costs one API request, so narrow large star lists with the filters."),
+		mcp.WithTitleAnnotation("Releases of Starred Repositories"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("since",
+			mcp.Required(),
+			mcp.Description("Only include releases published on or after this date (YYYY-MM-DD or RFC 3339)"),
+		),
+		mcp.WithBoolean("include_prereleases",
+			mcp.Description("Include prereleases (default false)"),
+		),
+		mcp.WithBoolean("include_notes",
+			mcp.Description("Include release notes (default false)"),
+		),
+		mcp.WithString("language",
+			mcp.Description("Only check repositories with this primary language, e.g. Go or TypeScript"),
+		),
+		mcp.WithString("owner",
+			mcp.Description("Only check repositories owned by this user or organization"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of repositories (default %d, max %d)", defaultToolLimit, maxToolLimit)),
+			mcp.Min(1),
+			mcp.Max(maxToolLimit),
+		),
+	)
+	for _, opt := range repoFilterOptions() {
+		opt(&releasesTool)
+	}
+
+	m.server.AddTool(releasesTool, m.handleStarredReleases)
+
+	if m.cfg.EnableWriteTools {
+		m.registerWriteTools()
+	}
+}
+
+// registerWriteTools sets up the tools that modify the authenticated user's
+// stars. They are only registered when write tools are enabled in the config.
+func (m *MCPServer) registerWriteTools() {
+	starTool := mcp.NewTool(
+		"star_repo",
+		mcp.WithDescription("Star a GitHub repository for the authenticated user. "+
+			"Starring an already starred repository does nothing. "+
+			"Set dry_run to check what would happen without changing anything."),
+		mcp.WithTitleAnnotation("Star Repository"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("repository",
+			mcp.Required(),
+			mcp.Description("Repository to star as owner/repo, e.g. mark3labs/mcp-go"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Report what would happen without starring (default false)"),
+		),
+	)
+
+	m.server.AddTool(starTool, m.handleStarRepo)
+
+	unstarTool := mcp.NewTool(
+		"unstar_repo",
+		mcp.WithDescription("Remove the authenticated user's star from a GitHub repository. "+
+			"Unstarring a repository that is not starred does nothing; the star date is lost once removed. "+
+			"Set dry_run to check what would happen without changing anything."),
+		mcp.WithTitleAnnotation("Unstar Repository"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("repository",
+			mcp.Required(),
+			mcp.Description("Repository to unstar as owner/repo, e.g. mark3labs/mcp-go"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Report what would happen without unstarring (default false)"),
+		),
+	)
+
+	m.server.AddTool(unstarTool, m.handleUnstarRepo)
+}
+
+// repoFilterOptions declares the repository metadata filters shared by the listing tools
+func repoFilterOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("topic",
+			mcp.Description("Only include repositories tagged with this topic"),
+		),
+		mcp.WithString("license",
+			mcp.Description("Only include repositories with this license, as an SPDX identifier such as MIT or Apache-2.0"),
+		),
+		mcp.WithString("visibility",
+			mcp.Description("Only include repositories with this visibility"),
+			mcp.Enum("public", "private", "internal"),
+		),
+		mcp.WithBoolean("archived",
+			mcp.Description("true for only archived repositories, false to skip them; omit to include both"),
+		),
+		mcp.WithBoolean("fork",
+			mcp.Description("true for only forks, false to skip them; omit to include both"),
+		),
+		mcp.WithBoolean("template",
+			mcp.Description("true for only template repositories, false to skip them; omit to include both"),
+		),
+	}
+}
+
+// toolRequestTimeout bounds each tool call with the configured timeout
+func toolRequestTimeout(timeout time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if timeout <= 0 {
+				return next(ctx, request)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+
+// handleSearchStarred handles calls to the search_starred tool
+func (m *MCPServer) handleSearchStarred(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts, err := searchOptionsFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	log.Printf("Searching starred repositories: %+v", opts)
+	return m.searchResult(ctx, opts)
+}
+
+// handleStarHistory handles calls to the star_history tool
+func (m *MCPServer) handleStarHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts, err := starHistoryOptions(
+		request.GetString("since", ""),
+		request.GetString("until", ""),
+		request.GetInt("limit", defaultToolLimit),
+	)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	opts.Order = request.GetString("order", "")
+	if err := applyRepoFilters(request, &opts); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := opts.Validate(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	log.Printf("Listing star history: %+v", opts)
+	return m.searchResult(ctx, opts)
+}
+
+// handleStarredReleases handles calls to the starred_releases tool
+func (m *MCPServer) handleStarredReleases(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts, err := releaseOptionsFromRequest(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	log.Printf("Listing releases of starred repositories since %s", opts.Since.Format(time.RFC3339))
+
+	repos, total, err := m.adapter.ReleasesSince(ctx, opts)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to list releases of starred repositories", err), nil
+	}
+
+	jsonData, err := json.MarshalIndent(map[string]interface{}{
+		"total_matches": total,
+		"returned":      len(repos),
+		"repositories":  repos,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal releases to JSON: %w", err)
+	}
+
+	log.Printf("Returning %d of %d starred repositories with releases", len(repos), total)
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// handleStarRepo handles calls to the star_repo tool
+func (m *MCPServer) handleStarRepo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return m.starResult(ctx, request, m.adapter.StarRepository)
+}
+
+// handleUnstarRepo handles calls to the unstar_repo tool
+func (m *MCPServer) handleUnstarRepo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return m.starResult(ctx, request, m.adapter.UnstarRepository)
+}
+
+// starResult runs a star or unstar call and formats its outcome as a tool result
+func (m *MCPServer) starResult(ctx context.Context, request mcp.CallToolRequest,
+	setStar func(ctx context.Context, fullName string, dryRun bool) (*resource.StarResult, error)) (*mcp.CallToolResult, error) {
+	fullName, err := request.RequireString("repository")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	dryRun := request.GetBool("dry_run", false)
+
+	result, err := setStar(ctx, fullName, dryRun)
+	if err != nil {
+		if errors.Is(err, github.ErrNotFound) {
+			return mcp.NewToolResultError(fmt.Sprintf("repository %s not found", fullName)), nil
+		}
+		return mcp.NewToolResultErrorFromErr("failed to update star", err), nil
+	}
+
+	log.Printf("%s %s: changed=%v dry_run=%v", result.Action, fullName, result.Changed, result.DryRun)
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal star result to JSON: %w", err)
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// searchResult runs a search and formats the matches as a tool result
+func (m *MCPServer) searchResult(ctx context.Context, opts resource.SearchOptions) (*mcp.CallToolResult, error) {
+	resources, total, err := m.adapter.SearchStarredResources(ctx, opts)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to search starred repositories", err), nil
+	}
+
+	jsonData, err := json.MarshalIndent(map[string]interface{}{
+		"total_matches": total,
+		"returned":      len(resources),
+		"repositories":  resources,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search results to JSON: %w", err)
+	}
+
+	log.Printf("Returning %d of %d matching starred repositories", len(resources), total)
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// handleRankStarred handles calls to the search_starred_ranked tool
+func (m *MCPServer) handleRankStarred(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	limit := clampLimit(request.GetInt("limit", defaultToolLimit))
+
+	log.Printf("Ranked search of starred repositories: %q", query)
+
+	resources, err := m.adapter.RankStarredResources(ctx, query, limit)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to search starred repositories", err), nil
+	}
+
+	jsonData, err := m.adapter.ToJSON(resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert resources to JSON: %w", err)
+	}
+
+	log.Printf("Returning %d ranked starred repositories", len(resources))
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// searchOptionsFromRequest builds search options from tool arguments
+func searchOptionsFromRequest(request mcp.CallToolRequest) (resource.SearchOptions, error) {
+	opts := resource.SearchOptions{
+		Query:    request.GetString("query", ""),
+		Language: request.GetString("language", ""),
+		MinStars: request.GetInt("min_stars", 0),
+		Owner:    request.GetString("owner", ""),
+		Sort:     request.GetString("sort", ""),
+		Order:    request.GetString("order", ""),
+		Limit:    clampLimit(request.GetInt("limit", defaultToolLimit)),
+	}
+
+	if since := request.GetString("updated_since", ""); since != "" {
+		t, err := parseDate(since)
+		if err != nil {
+			return opts, fmt.Errorf("invalid updated_since: %w", err)
+		}
+		opts.UpdatedSince = t
+	}
+
+	var err error
+	opts.StarredSince, opts.StarredUntil, err = parseDateRange(
+		request.GetString("starred_since", ""),
+		request.GetString("starred_until", ""),
+	)
+	if err != nil {
+		return opts, err
+	}
+
+	if err := applyRepoFilters(request, &opts); err != nil {
+		return opts, err
+	}
+
+	return opts, opts.Validate()
+}
+
+// applyRepoFilters copies the repository metadata filters from tool arguments into opts
+func applyRepoFilters(request mcp.CallToolRequest, opts *resource.SearchOptions) error {
+	opts.Topic = request.GetString("topic", "")
+	opts.License = request.GetString("license", "")
+	opts.Visibility = request.GetString("visibility", "")
+
+	var err error
+	if opts.Archived, err = optionalBool(request, "archived"); err != nil {
+		return err
+	}
+	if opts.Fork, err = optionalBool(request, "fork"); err != nil {
+		return err
+	}
+	if opts.Template, err = optionalBool(request, "template"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// releaseOptionsFromRequest builds release scan options from starred_releases arguments
+func releaseOptionsFromRequest(request mcp.CallToolRequest) (resource.ReleaseOptions, error) {
+	opts := resource.ReleaseOptions{
+		IncludePrereleases: request.GetBool("include_prereleases", false),
+		IncludeNotes:       request.GetBool("include_notes", false),
+		Filter: resource.SearchOptions{
+			Language: request.GetString("language", ""),
+			Owner:    request.GetString("owner", ""),
+		},
+		Limit: clampLimit(request.GetInt("limit", defaultToolLimit)),
+	}
+
+	since, err := request.RequireString("since")
+	if err != nil {
+		return opts, err
+	}
+	if opts.Since, err = parseDate(since); err != nil {
+		return opts, fmt.Errorf("invalid since: %w", err)
+	}
+
+	if err := applyRepoFilters(request, &opts.Filter); err != nil {
+		return opts, err
+	}
+
+	return opts, opts.Filter.Validate()
+}
+
+// optionalBool returns a boolean argument, or nil when it was not given
+func optionalBool(request mcp.CallToolRequest, key string) (*bool, error) {
+	if value, ok := request.GetArguments()[key]; !ok || value == nil {
+		return nil, nil
+	}
+
+	b, err := request.RequireBool(key)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// starHistoryOptions builds search options listing stars between since and
+// until, newest first. Either bound may be empty.
+func starHistoryOptions(since, until string, limit int) (resource.SearchOptions, error) {
+	opts := resource.SearchOptions{
+		Sort:  resource.SortByStarred,
+		Limit: clampLimit(limit),
+	}
+
+	var err error
+	opts.StarredSince, opts.StarredUntil, err = parseDateRange(since, until)
+	return opts, err
+}
+
+// parseDateRange parses optional inclusive date bounds. An until given as a
+// plain date covers that whole day.
+func parseDateRange(since, until string) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+
+	if since != "" {
+		start, err = parseDate(since)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+		}
+	}
+
+	if until != "" {
+		end, err = parseDate(until)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+		}
+		if _, dateErr := time.Parse(time.DateOnly, until); dateErr == nil {
+			end = end.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
+
+	return start, end, nil
+}
+
+// clampLimit keeps a requested result count within the tool limits
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return defaultToolLimit
+	}
+	if limit > maxToolLimit {
+		return maxToolLimit
+	}
+	return limit
+}
+
+// parseDate parses a date given as YYYY-MM-DD or RFC 3339
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a YYYY-MM-DD or RFC 3339 date", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/timduly4/mcp-server/internal/config"
+	"github.com/timduly4/mcp-server/internal/resource"
+)
+
+func newCallToolRequest(args map[string]any) mcp.CallToolRequest {
+	var request mcp.CallToolRequest
+	request.Params.Arguments = args
+	return request
+}
+
+// TestSearchOptionsFromRequest tests conversion of search_starred arguments
+func TestSearchOptionsFromRequest(t *testing.T) {
+	request := newCallToolRequest(map[string]any{
+		"query":         "mcp",
+		"language":      "Go",
+		"min_stars":     float64(1000),
+		"updated_since": "2024-01-01",
+		"sort":          resource.SortByStars,
+		"limit":         float64(500),
+	})
+
+	opts, err := searchOptionsFromRequest(request)
+	if err != nil {
+		t.Fatalf("searchOptionsFromRequest() error = %v", err)
+	}
+
+	if opts.Query != "mcp" || opts.Language != "Go" || opts.MinStars != 1000 {
+		t.Errorf("opts = %+v, want query mcp, language Go, min stars 1000", opts)
+	}
+	if !opts.UpdatedSince.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("UpdatedSince = %v, want 2024-01-01", opts.UpdatedSince)
+	}
+	if opts.Limit != maxToolLimit {
+		t.Errorf("Limit = %d, want %d (clamped)", opts.Limit, maxToolLimit)
+	}
+}
+
+// TestApplyRepoFilters tests that omitted flag filters stay unset
+func TestApplyRepoFilters(t *testing.T) {
+	request := newCallToolRequest(map[string]any{
+		"topic":    "cli",
+		"license":  "MIT",
+		"archived": false,
+		"fork":     "true",
+	})
+
+	var opts resource.SearchOptions
+	if err := applyRepoFilters(request, &opts); err != nil {
+		t.Fatalf("applyRepoFilters() error = %v", err)
+	}
+
+	if opts.Topic != "cli" || opts.License != "MIT" {
+		t.Errorf("opts = %+v, want topic cli and license MIT", opts)
+	}
+	if opts.Archived == nil || *opts.Archived {
+		t.Errorf("Archived = %v, want false", opts.Archived)
+	}
+	if opts.Fork == nil || !*opts.Fork {
+		t.Errorf("Fork = %v, want true", opts.Fork)
+	}
+	if opts.Template != nil {
+		t.Errorf("Template = %v, want nil when omitted", *opts.Template)
+	}
+
+	if err := applyRepoFilters(newCallToolRequest(map[string]any{"archived": "maybe"}), &opts); err == nil {
+		t.Error("applyRepoFilters() expected error for a non-boolean flag, got nil")
+	}
+}
+
+// TestSearchOptionsFromRequest_Invalid tests that bad arguments are rejected
+func TestSearchOptionsFromRequest_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{
+			name: "bad date",
+			args: map[string]any{"updated_since": "last year"},
+		},
+		{
+			name: "bad sort",
+			args: map[string]any{"sort": "popularity"},
+		},
+		{
+			name: "bad star date",
+			args: map[string]any{"starred_since": "yesterday"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := searchOptionsFromRequest(newCallToolRequest(tt.args)); err == nil {
+				t.Error("searchOptionsFromRequest() expected error, got nil")
+			}
+		})
+	}
+}
+
+// TestParseDate tests accepted date formats
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "date only",
+			value:    "2024-03-15",
+			expected: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "RFC 3339",
+			value:    "2024-03-15T10:30:00Z",
+			expected: time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid",
+			value:   "15/03/2024",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseDate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("parseDate(%q) = %v, want %v", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestReleaseOptionsFromRequest tests conversion of starred_releases arguments
+func TestReleaseOptionsFromRequest(t *testing.T) {
+	request := newCallToolRequest(map[string]any{
+		"since":               "2024-06-01",
+		"include_prereleases": true,
+		"language":            "Go",
+		"topic":               "mcp",
+	})
+
+	opts, err := releaseOptionsFromRequest(request)
+	if err != nil {
+		t.Fatalf("releaseOptionsFromRequest() error = %v", err)
+	}
+
+	if !opts.Since.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Since = %v, want 2024-06-01", opts.Since)
+	}
+	if !opts.IncludePrereleases || opts.IncludeNotes {
+		t.Errorf("opts = %+v, want prereleases without notes", opts)
+	}
+	if opts.Filter.Language != "Go" || opts.Filter.Topic != "mcp" {
+		t.Errorf("Filter = %+v, want language Go and topic mcp", opts.Filter)
+	}
+	if opts.Limit != defaultToolLimit {
+		t.Errorf("Limit = %d, want %d", opts.Limit, defaultToolLimit)
+	}
+
+	for _, args := range []map[string]any{
+		{},
+		{"since": "last week"},
+	} {
+		if _, err := releaseOptionsFromRequest(newCallToolRequest(args)); err == nil {
+			t.Errorf("releaseOptionsFromRequest(%v) expected error, got nil", args)
+		}
+	}
+}
+
+// TestWriteToolsGatedByConfig tests that star_repo and unstar_repo are only
+// exposed when write tools are enabled, with the expected annotations
+func TestWriteToolsGatedByConfig(t *testing.T) {
+	readOnly := NewMCPServer(&config.Config{}, nil)
+	for _, name := range []string{"star_repo", "unstar_repo"} {
+		if readOnly.server.GetTool(name) != nil {
+			t.Errorf("tool %s registered with write tools disabled", name)
+		}
+	}
+
+	writable := NewMCPServer(&config.Config{EnableWriteTools: true}, nil)
+	tests := []struct {
+		name        string
+		destructive bool
+	}{
+		{name: "star_repo", destructive: false},
+		{name: "unstar_repo", destructive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := writable.server.GetTool(tt.name)
+			if tool == nil {
+				t.Fatalf("tool %s not registered with write tools enabled", tt.name)
+			}
+
+			annotations := tool.Tool.Annotations
+			if annotations.ReadOnlyHint == nil || *annotations.ReadOnlyHint {
+				t.Errorf("ReadOnlyHint = %v, want false", annotations.ReadOnlyHint)
+			}
+			if annotations.DestructiveHint == nil || *annotations.DestructiveHint != tt.destructive {
+				t.Errorf("DestructiveHint = %v, want %v", annotations.DestructiveHint, tt.destructive)
+			}
+			if annotations.IdempotentHint == nil || !*annotations.IdempotentHint {
+				t.Errorf("IdempotentHint = %v, want true", annotations.IdempotentHint)
+			}
+		})
+	}
+}
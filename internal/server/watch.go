@@ -0,0 +1,255 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/timduly4/mcp-server/internal/resource"
+)
+
+// starredListPaths are the resources whose contents change whenever the
+// authenticated user's star list does
+var starredListPaths = []string{
+	"starred",
+	"starred/history",
+	"starred/search",
+}
+
+// Watch keeps the repository resources in sync with the star list and
+// notifies subscribers when stars are added or removed. The star list is
+// read once immediately and then every PollInterval until ctx is cancelled.
+// Polling is disabled in multi-tenant mode, where the server has no GitHub
+// identity of its own. A GitHub App installation has no star list either, so
+// only the star lists of users with subscribers are polled for it.
+func (m *MCPServer) Watch(ctx context.Context) {
+	if m.cfg.MultiTenant {
+		log.Println("Star list polling is disabled in multi-tenant mode")
+		return
+	}
+	if m.adapter.IsInstallation() {
+		log.Println("GitHub App installations have no starred repositories, so resources/list does not list any")
+	}
+
+	m.poll(ctx)
+
+	if m.cfg.PollInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.poll(ctx)
+		}
+	}
+}
+
+// poll refreshes the authenticated user's star list and those of users
+// with subscribers
+func (m *MCPServer) poll(ctx context.Context) {
+	if !m.adapter.IsInstallation() {
+		if err := m.pollStarred(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to poll starred repositories: %v", err)
+		}
+	}
+
+	usernames := m.subs.usernames()
+	for _, username := range usernames {
+		if err := m.pollUserStarred(ctx, username); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to poll starred repositories for user %s: %v", username, err)
+		}
+	}
+
+	m.pruneStars(usernames)
+}
+
+// pollStarred diffs the authenticated user's star list against the last poll
+func (m *MCPServer) pollStarred(ctx context.Context) error {
+	ctx, cancel := m.pollContext(ctx)
+	defer cancel()
+
+	resources, err := m.adapter.ListStarredResources(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list starred resources: %w", err)
+	}
+
+	m.registerRepoResources(resources)
+
+	added, removed, changed := m.updateStars("", resources)
+	if !changed {
+		return nil
+	}
+
+	log.Printf("Star list changed: %d added, %d removed", len(added), len(removed))
+
+	uris := m.starredListURIs()
+	for _, fullName := range append(added, removed...) {
+		uris = append(uris, m.uri("starred/"+fullName))
+	}
+	m.notifyUpdated(uris...)
+	return nil
+}
+
+// starredListURIs returns the URIs of the starredListPaths resources
+func (m *MCPServer) starredListURIs() []string {
+	uris := make([]string, 0, len(starredListPaths))
+	for _, path := range starredListPaths {
+		uris = append(uris, m.uri(path))
+	}
+	return uris
+}
+
+// pollUserStarred diffs a user's star list against the last poll
+func (m *MCPServer) pollUserStarred(ctx context.Context, username string) error {
+	ctx, cancel := m.pollContext(ctx)
+	defer cancel()
+
+	resources, err := m.adapter.ListStarredResourcesForUser(ctx, username)
+	if err != nil {
+		return fmt.Errorf("failed to list starred resources: %w", err)
+	}
+
+	added, removed, changed := m.updateStars(username, resources)
+	if !changed {
+		return nil
+	}
+
+	log.Printf("Star list of %s changed: %d added, %d removed", username, len(added), len(removed))
+	m.notifyUpdated(m.uri("starred/users/" + username))
+	return nil
+}
+
+// pollContext bounds a single poll with the configured request timeout
+func (m *MCPServer) pollContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if m.cfg.RequestTimeout > 0 {
+		return context.WithTimeout(ctx, m.cfg.RequestTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// registerRepoResources registers every starred repository as an individual
+// resource so clients can enumerate them through the paginated resources/list.
+// Only new and removed repositories are touched, so clients are sent
+// notifications/resources/list_changed just when the list actually changes.
+func (m *MCPServer) registerRepoResources(resources []resource.MCPResource) {
+	current := make(map[string]bool, len(resources))
+	var added []server.ServerResource
+
+	m.mu.Lock()
+	for _, r := range resources {
+		current[r.URI] = true
+		if m.repoURIs[r.URI] {
+			continue
+		}
+		added = append(added, server.ServerResource{
+			Resource: mcp.NewResource(
+				r.URI,
+				r.Name,
+				mcp.WithResourceDescription(r.Description),
+				mcp.WithMIMEType(r.MimeType),
+			),
+			// The starred/{owner}/{repo} template already read the
+			// repository ahead of mcp-go
+			Handler: readAheadHandler(m.handleGetStarredRepo),
+		})
+	}
+
+	var stale []string
+	for uri := range m.repoURIs {
+		if !current[uri] {
+			stale = append(stale, uri)
+		}
+	}
+	m.repoURIs = current
+	m.mu.Unlock()
+
+	if len(stale) > 0 {
+		m.server.DeleteResources(stale...)
+	}
+	if len(added) > 0 {
+		m.server.AddResources(added...)
+	}
+}
+
+// updateStars records the star list of username and returns the full names
+// added and removed since the previous poll. The first poll of a user only
+// records the list and reports no change.
+func (m *MCPServer) updateStars(username string, resources []resource.MCPResource) ([]string, []string, bool) {
+	current := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		current[r.Name] = true
+	}
+
+	m.mu.Lock()
+	previous, seen := m.stars[username]
+	m.stars[username] = current
+	m.mu.Unlock()
+
+	if !seen {
+		return nil, nil, false
+	}
+
+	added, removed := diffStars(previous, current)
+	return added, removed, len(added) > 0 || len(removed) > 0
+}
+
+// pruneStars forgets the star lists of users that no longer have subscribers
+func (m *MCPServer) pruneStars(usernames []string) {
+	keep := make(map[string]bool, len(usernames)+1)
+	keep[""] = true
+	for _, username := range usernames {
+		keep[username] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for username := range m.stars {
+		if !keep[username] {
+			delete(m.stars, username)
+		}
+	}
+}
+
+// notifyUpdated sends notifications/resources/updated to every session
+// subscribed to one of uris, including paginated or parameterised variants
+func (m *MCPServer) notifyUpdated(uris ...string) {
+	for sessionID, subscribed := range m.subs.matching(uris...) {
+		for _, uri := range subscribed {
+			err := m.server.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{
+				"uri": uri,
+			})
+			if err != nil {
+				log.Printf("Failed to notify session %s of update to %s: %v", sessionID, uri, err)
+			}
+		}
+	}
+}
+
+// diffStars returns the names present only in current (added) and only in previous (removed), sorted
+func diffStars(previous, current map[string]bool) ([]string, []string) {
+	var added, removed []string
+	for name := range current {
+		if !previous[name] {
+			added = append(added, name)
+		}
+	}
+	for name := range previous {
+		if !current[name] {
+			removed = append(removed, name)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
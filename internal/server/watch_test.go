@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/timduly4/mcp-server/internal/config"
+	"github.com/timduly4/mcp-server/internal/github"
+	"github.com/timduly4/mcp-server/internal/resource"
+)
+
+func TestDiffStars(t *testing.T) {
+	previous := map[string]bool{"a/one": true, "b/two": true}
+	current := map[string]bool{"b/two": true, "c/three": true, "a/four": true}
+
+	added, removed := diffStars(previous, current)
+	if !reflect.DeepEqual(added, []string{"a/four", "c/three"}) {
+		t.Errorf("added = %v, want [a/four c/three]", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"a/one"}) {
+		t.Errorf("removed = %v, want [a/one]", removed)
+	}
+}
+
+// TestUpdateStars tests that the first poll is a baseline and later polls report changes
+func TestUpdateStars(t *testing.T) {
+	srv := NewMCPServer(&config.Config{}, nil)
+	first := []resource.MCPResource{{Name: "a/one"}, {Name: "b/two"}}
+
+	if _, _, changed := srv.updateStars("", first); changed {
+		t.Error("updateStars() reported a change on the first poll")
+	}
+	if _, _, changed := srv.updateStars("", first); changed {
+		t.Error("updateStars() reported a change for an identical list")
+	}
+
+	added, removed, changed := srv.updateStars("", []resource.MCPResource{{Name: "b/two"}, {Name: "c/three"}})
+	if !changed || !reflect.DeepEqual(added, []string{"c/three"}) || !reflect.DeepEqual(removed, []string{"a/one"}) {
+		t.Errorf("updateStars() = %v, %v, %v; want [c/three], [a/one], true", added, removed, changed)
+	}
+
+	srv.pruneStars(nil)
+	if _, ok := srv.stars[""]; !ok {
+		t.Error("pruneStars() dropped the authenticated user's star list")
+	}
+}
+
+// TestRegisterRepoResources tests that stale repositories are removed from resources/list
+func TestRegisterRepoResources(t *testing.T) {
+	srv := NewMCPServer(&config.Config{}, nil)
+
+	srv.registerRepoResources([]resource.MCPResource{
+		{URI: "github://starred/a/one", Name: "a/one", MimeType: "application/json"},
+		{URI: "github://starred/b/two", Name: "b/two", MimeType: "application/json"},
+	})
+	srv.registerRepoResources([]resource.MCPResource{
+		{URI: "github://starred/b/two", Name: "b/two", MimeType: "application/json"},
+	})
+
+	resources := srv.server.ListResources()
+	if _, ok := resources["github://starred/a/one"]; ok {
+		t.Error("unstarred repository still listed")
+	}
+	if _, ok := resources["github://starred/b/two"]; !ok {
+		t.Error("starred repository missing from resources")
+	}
+}
+
+// TestPoll_Installation tests that a GitHub App installation, which has no
+// star list, does not poll one
+func TestPoll_Installation(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	viewerPolls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"ghs_1","expires_at":%q}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	})
+	mux.HandleFunc("/api/v3/user/starred", func(w http.ResponseWriter, r *http.Request) {
+		viewerPolls++
+		w.WriteHeader(http.StatusForbidden)
+	})
+	gh := httptest.NewServer(mux)
+	defer gh.Close()
+
+	client, err := github.NewClient(context.Background(), "",
+		github.WithEnterpriseURLs(gh.URL, ""), github.WithAppInstallation(7, 42, pemKey), github.WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	srv := NewMCPServer(&config.Config{}, resource.NewAdapter(client, nil))
+
+	srv.poll(context.Background())
+
+	if viewerPolls != 0 {
+		t.Errorf("polled the viewer's star list %d times, want 0", viewerPolls)
+	}
+}
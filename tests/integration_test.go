@@ -3,7 +3,11 @@ package tests
 import (
 	"context"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/timduly4/mcp-server/internal/config"
 	"github.com/timduly4/mcp-server/internal/github"
@@ -19,10 +23,13 @@ func TestIntegration_GitHubClient(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	client := github.NewClient(ctx, token)
+	client, err := github.NewClient(ctx, token)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
 
 	// Test fetching starred repos
-	repos, err := client.GetStarredRepos()
+	repos, err := client.GetStarredRepos(ctx)
 	if err != nil {
 		t.Fatalf("Failed to fetch starred repos: %v", err)
 	}
@@ -54,11 +61,14 @@ func TestIntegration_ResourceAdapter(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	client := github.NewClient(ctx, token)
-	adapter := resource.NewAdapter(client)
+	client, err := github.NewClient(ctx, token)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	adapter := resource.NewAdapter(client, nil)
 
 	// Test listing starred resources
-	resources, err := adapter.ListStarredResources()
+	resources, err := adapter.ListStarredResources(ctx)
 	if err != nil {
 		t.Fatalf("Failed to list starred resources: %v", err)
 	}
@@ -107,7 +117,7 @@ func TestIntegration_Config(t *testing.T) {
 	testToken := "test_token_123"
 	os.Setenv("GITHUB_TOKEN", testToken)
 
-	cfg, err := config.Load()
+	cfg, err := config.Load(nil)
 	if err != nil {
 		t.Fatalf("Failed to load config: %v", err)
 	}
@@ -123,6 +133,57 @@ func TestIntegration_Config(t *testing.T) {
 	if cfg.ServerHost != "localhost" {
 		t.Errorf("ServerHost = %s, want localhost", cfg.ServerHost)
 	}
+	if cfg.Transport != config.TransportStdio {
+		t.Errorf("Transport = %s, want %s", cfg.Transport, config.TransportStdio)
+	}
+	if cfg.Address() != "localhost:8080" {
+		t.Errorf("Address() = %s, want localhost:8080", cfg.Address())
+	}
+	if !cfg.CacheEnabled {
+		t.Error("CacheEnabled = false, want true")
+	}
+	if cfg.CacheTTL != 5*time.Minute {
+		t.Errorf("CacheTTL = %v, want 5m", cfg.CacheTTL)
+	}
+	if cfg.PageSize != 50 {
+		t.Errorf("PageSize = %d, want 50", cfg.PageSize)
+	}
+	if cfg.PollInterval != 5*time.Minute {
+		t.Errorf("PollInterval = %v, want 5m", cfg.PollInterval)
+	}
+}
+
+// TestIntegration_ConfigInvalidTransport tests config loading with an unknown transport
+func TestIntegration_ConfigInvalidTransport(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test_token_123")
+	t.Setenv("MCP_TRANSPORT", "websocket")
+
+	_, err := config.Load(nil)
+	if err == nil {
+		t.Error("Expected error for invalid MCP_TRANSPORT, got nil")
+	}
+}
+
+// TestIntegration_ConfigInvalidCacheTTL tests config loading with a malformed cache TTL
+func TestIntegration_ConfigInvalidCacheTTL(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test_token_123")
+	t.Setenv("CACHE_TTL", "five minutes")
+
+	_, err := config.Load(nil)
+	if err == nil {
+		t.Error("Expected error for invalid CACHE_TTL, got nil")
+	}
+}
+
+// TestIntegration_ConfigInvalidPageSize tests config loading with an out-of-range page size
+func TestIntegration_ConfigInvalidPageSize(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test_token_123")
+	t.Setenv("RESOURCE_PAGE_SIZE", "500")
+
+	_, err := config.Load(nil)
+	if err == nil {
+		t.Error("Expected error for invalid RESOURCE_PAGE_SIZE, got nil")
+	}
 }
 
 // TestIntegration_ConfigMissingToken tests config loading without token
@@ -133,9 +194,193 @@ func TestIntegration_ConfigMissingToken(t *testing.T) {
 
 	// Unset token
 	os.Unsetenv("GITHUB_TOKEN")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
 
-	_, err := config.Load()
+	_, err := config.Load(nil)
 	if err == nil {
 		t.Error("Expected error when GITHUB_TOKEN is missing, got nil")
 	}
 }
+
+// TestIntegration_ConfigFile tests config loading from YAML and TOML files
+func TestIntegration_ConfigFile(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+github_token: file_token
+mcp_transport: http
+cache:
+  ttl: 10m
+  enabled: false
+rate_limit:
+  max_retries: 5
+`,
+		"config.toml": `
+github_token = "file_token"
+mcp_transport = "http"
+rate_limit_max_retries = 5
+
+[cache]
+ttl = "10m"
+enabled = false
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", "")
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := config.Load([]string{"--config", path})
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if cfg.GitHubToken != "file_token" || cfg.Transport != config.TransportStreamableHTTP {
+				t.Errorf("token, transport = %q, %q, want file_token, http", cfg.GitHubToken, cfg.Transport)
+			}
+			if cfg.CacheTTL != 10*time.Minute || cfg.CacheEnabled || cfg.RateLimitMaxRetries != 5 {
+				t.Errorf("cache TTL, enabled, retries = %v, %v, %d, want 10m, false, 5",
+					cfg.CacheTTL, cfg.CacheEnabled, cfg.RateLimitMaxRetries)
+			}
+		})
+	}
+}
+
+// TestIntegration_ConfigPrecedence tests that flags override environment
+// variables, which override the config file
+func TestIntegration_ConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "github_token: file_token\ncache_ttl: 10m\nserver_port: 9000\npoll_interval: 1m\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("CACHE_TTL", "20m")
+	t.Setenv("SERVER_PORT", "9001")
+
+	cfg, err := config.Load([]string{"--server-port", "9002"})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.PollInterval != time.Minute {
+		t.Errorf("PollInterval = %v, want the file's 1m", cfg.PollInterval)
+	}
+	if cfg.CacheTTL != 20*time.Minute {
+		t.Errorf("CacheTTL = %v, want the environment's 20m", cfg.CacheTTL)
+	}
+	if cfg.ServerPort != "9002" {
+		t.Errorf("ServerPort = %s, want the flag's 9002", cfg.ServerPort)
+	}
+}
+
+// TestIntegration_ConfigErrors tests that invalid settings name where they were set
+func TestIntegration_ConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		args    []string
+		wantErr string
+	}{
+		{name: "file value", file: "cache:\n  ttl: soon\n", wantErr: "invalid cache.ttl in "},
+		{name: "flag value", args: []string{"--cache-ttl", "soon"}, wantErr: "invalid --cache-ttl"},
+		{name: "unknown file key", file: "cache:\n  size: 10\n", wantErr: "unknown setting cache.size"},
+		{name: "duplicate file key", file: "cache_ttl: 1m\ncache:\n  ttl: 2m\n", wantErr: "set the same value"},
+		{name: "unknown flag", args: []string{"--cache-size", "10"}, wantErr: "flag provided but not defined"},
+		{name: "secret flag", args: []string{"--github-token", "ghp_x"}, wantErr: "flag provided but not defined"},
+		{name: "unsupported file", args: []string{"--config", "config.json"}, wantErr: "expected a .yaml, .yml or .toml extension"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", "test_token_123")
+			args := tt.args
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				args = append(args, "--config", path)
+			}
+
+			_, err := config.Load(args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestIntegration_ConfigTokenSources tests reading the GitHub token from a
+// file, a credential helper and the gh CLI's hosts file
+func TestIntegration_ConfigTokenSources(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file_token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ghDir := t.TempDir()
+	hosts := "github.com:\n    user: octocat\n    oauth_token: gh_token\ngithub.example.com:\n    oauth_token: ghes_token\n"
+	if err := os.WriteFile(filepath.Join(ghDir, "hosts.yml"), []byte(hosts), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		ghDir     string
+		wantToken string
+		wantErr   string
+	}{
+		{name: "token file", env: map[string]string{"GITHUB_TOKEN_FILE": tokenFile}, wantToken: "file_token"},
+		{name: "empty token file", env: map[string]string{"GITHUB_TOKEN_FILE": emptyFile}, wantErr: "is empty"},
+		{name: "missing token file", env: map[string]string{"GITHUB_TOKEN_FILE": filepath.Join(dir, "missing")}, wantErr: "failed to read GITHUB_TOKEN_FILE"},
+		{name: "token command", env: map[string]string{"GITHUB_TOKEN_COMMAND": "echo command_token"}, wantToken: "command_token"},
+		{name: "failing token command", env: map[string]string{"GITHUB_TOKEN_COMMAND": "false"}, wantErr: "invalid GITHUB_TOKEN_COMMAND"},
+		{name: "token and token file", env: map[string]string{"GITHUB_TOKEN": "env_token", "GITHUB_TOKEN_FILE": tokenFile}, wantErr: "mutually exclusive"},
+		{name: "gh hosts file", ghDir: ghDir, wantToken: "gh_token"},
+		{name: "gh hosts file for GHES", env: map[string]string{"GITHUB_BASE_URL": "https://github.example.com/api/v3/"}, ghDir: ghDir, wantToken: "ghes_token"},
+		{name: "explicit token wins over gh", env: map[string]string{"GITHUB_TOKEN": "env_token"}, ghDir: ghDir, wantToken: "env_token"},
+		{name: "no token anywhere", wantErr: "a GitHub token is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if runtime.GOOS == "windows" && tt.env["GITHUB_TOKEN_COMMAND"] != "" {
+				t.Skip("echo and false are not executables on Windows")
+			}
+
+			t.Setenv("GITHUB_TOKEN", "")
+			ghConfigDir := tt.ghDir
+			if ghConfigDir == "" {
+				ghConfigDir = t.TempDir()
+			}
+			t.Setenv("GH_CONFIG_DIR", ghConfigDir)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load(nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if cfg.GitHubToken != tt.wantToken {
+				t.Errorf("GitHubToken = %q, want %q", cfg.GitHubToken, tt.wantToken)
+			}
+		})
+	}
+}